@@ -0,0 +1,425 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+	"github.com/VictorAvelar/goh-dear/ohdear/sla"
+)
+
+// Operations reported by the scrape errors counter.
+const (
+	opListSites         = "list_sites"
+	opUptime            = "uptime"
+	opCertificateHealth = "certificate_health"
+)
+
+var operations = []string{opListSites, opUptime, opCertificateHealth}
+
+// options controls how often the oh-dear API is polled.
+type options struct {
+	// Interval between two refreshes of the sites list.
+	Interval time.Duration
+	// UptimeInterval is the maximum age of the uptime percentages.
+	UptimeInterval time.Duration
+	// CertificateInterval is the maximum age of the certificate health.
+	CertificateInterval time.Duration
+	// Windows are the periods covered by the uptime percentages.
+	Windows []time.Duration
+	// TeamID restricts the exported sites to a team.
+	TeamID uint
+	// PageSize is the amount of sites requested per page.
+	PageSize uint
+	// RequestsPerMinute limits the calls made to the API,
+	// zero disables the limit.
+	RequestsPerMinute int
+}
+
+// siteState is the cached view of a site, it is never
+// modified once published.
+type siteState struct {
+	site        *ohdear.Site
+	uptime      map[time.Duration]float64
+	uptimeAt    time.Time
+	certificate *ohdear.CertificateHealth
+	certAt      time.Time
+}
+
+// collector polls the oh-dear API in the background and serves the
+// cached results, scrapes never trigger API calls.
+type collector struct {
+	client  *ohdear.Client
+	opts    options
+	logger  *log.Logger
+	now     func() time.Time
+	limiter *time.Ticker
+
+	mu          sync.RWMutex
+	sites       []*siteState
+	errors      map[string]float64
+	lastSuccess time.Time
+	lastRefresh time.Duration
+}
+
+func newCollector(client *ohdear.Client, opts options, logger *log.Logger) *collector {
+	c := &collector{
+		client: client,
+		opts:   opts,
+		logger: logger,
+		now:    time.Now,
+		errors: make(map[string]float64, len(operations)),
+	}
+
+	if opts.RequestsPerMinute > 0 {
+		c.limiter = time.NewTicker(time.Minute / time.Duration(opts.RequestsPerMinute))
+	}
+
+	for _, op := range operations {
+		c.errors[op] = 0
+	}
+
+	return c
+}
+
+// run refreshes the cache until the context is cancelled.
+func (c *collector) run(ctx context.Context) {
+	t := time.NewTicker(c.opts.Interval)
+	defer t.Stop()
+
+	if c.limiter != nil {
+		defer c.limiter.Stop()
+	}
+
+	for {
+		if err := c.refresh(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Printf("refresh failed: %v", err)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh lists the sites and updates the uptime and certificate
+// health of the sites whose cached values are stale.
+//
+// Failures keep the previously cached values, only a failure
+// listing the sites aborts the refresh.
+func (c *collector) refresh(ctx context.Context) error {
+	start := c.now()
+
+	sites, err := c.listSites(ctx)
+	if err != nil {
+		c.failed(opListSites)
+		return err
+	}
+
+	c.mu.RLock()
+	prev := make(map[uint]*siteState, len(c.sites))
+	for _, st := range c.sites {
+		prev[st.site.ID] = st
+	}
+	c.mu.RUnlock()
+
+	states := make([]*siteState, 0, len(sites))
+	for _, s := range sites {
+		st := &siteState{site: s}
+		if old, ok := prev[s.ID]; ok {
+			st.uptime, st.uptimeAt = old.uptime, old.uptimeAt
+			st.certificate, st.certAt = old.certificate, old.certAt
+		}
+
+		if len(c.opts.Windows) > 0 && c.stale(st.uptimeAt, c.opts.UptimeInterval) {
+			if err := c.refreshUptime(ctx, st); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				c.failed(opUptime)
+				c.logger.Printf("site %d uptime: %v", s.ID, err)
+			}
+		}
+
+		if s.UsesHTTPS && c.stale(st.certAt, c.opts.CertificateInterval) {
+			if err := c.refreshCertificate(ctx, st); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				c.failed(opCertificateHealth)
+				c.logger.Printf("site %d certificate health: %v", s.ID, err)
+			}
+		}
+
+		states = append(states, st)
+	}
+
+	c.mu.Lock()
+	c.sites = states
+	c.lastSuccess = c.now()
+	c.lastRefresh = c.lastSuccess.Sub(start)
+	c.mu.Unlock()
+
+	return nil
+}
+
+// listSites walks all the pages of sites using SitesSrv.ListAll.
+func (c *collector) listSites(ctx context.Context) ([]*ohdear.Site, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.client.Sites.ListAll(ctx, ohdear.ListSitesRequestFilters{
+		PageSize:       c.opts.PageSize,
+		PageNumber:     1,
+		FilterByTeamID: c.opts.TeamID,
+	})
+}
+
+func (c *collector) refreshUptime(ctx context.Context, st *siteState) error {
+	calc := &sla.Calculator{Uptime: c.client.Sites, Target: 100, Concurrency: 1}
+	end := c.now()
+	uptime := make(map[time.Duration]float64, len(c.opts.Windows))
+
+	for _, d := range c.opts.Windows {
+		if err := c.wait(ctx); err != nil {
+			return err
+		}
+
+		calc.Split = ohdear.SplitByHour
+		if d > 48*time.Hour {
+			calc.Split = ohdear.SplitByDay
+		}
+
+		r, err := calc.Compute(ctx, []*ohdear.Site{st.site}, sla.Window{Start: end.Add(-d), End: end})
+		if err != nil {
+			return err
+		}
+
+		if sr := r.Sites[0]; !sr.NoData {
+			uptime[d] = sr.Availability
+		}
+	}
+
+	st.uptime, st.uptimeAt = uptime, end
+
+	return nil
+}
+
+func (c *collector) refreshCertificate(ctx context.Context, st *siteState) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	ch, err := c.client.CertificateHealth.Get(st.site.ID)
+	if err != nil {
+		return err
+	}
+
+	st.certificate, st.certAt = ch, c.now()
+
+	return nil
+}
+
+func (c *collector) stale(at time.Time, maxAge time.Duration) bool {
+	return at.IsZero() || c.now().Sub(at) >= maxAge
+}
+
+// wait blocks until the rate limit allows a new API call.
+func (c *collector) wait(ctx context.Context) error {
+	if c.limiter == nil {
+		return ctx.Err()
+	}
+
+	select {
+	case <-c.limiter.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *collector) failed(op string) {
+	c.mu.Lock()
+	c.errors[op]++
+	c.mu.Unlock()
+}
+
+// ServeHTTP writes the cached metrics, they are rendered in memory
+// so a slow client does not hold the lock of the cache.
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := c.write(newMetricsWriter(&buf)); err != nil {
+		c.logger.Printf("rendering metrics: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", openMetricsContentType)
+	if _, err := buf.WriteTo(w); err != nil {
+		c.logger.Printf("writing metrics: %v", err)
+	}
+}
+
+func (c *collector) write(mw *metricsWriter) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+
+	mw.family("ohdear_site_info", gaugeType, "Monitored sites, the value is always 1.")
+	for _, st := range c.sites {
+		s := st.site
+		mw.sample("ohdear_site_info", 1, siteLabels(s,
+			label{"label", s.Label},
+			label{"team_id", strconv.FormatUint(uint64(s.TeamID), 10)},
+			label{"monitor_type", string(s.Kind())},
+		)...)
+	}
+
+	mw.family("ohdear_site_result", gaugeType, "Summarized result of the checks of a site, 1 for the current result.")
+	for _, st := range c.sites {
+		writeResult(mw, "ohdear_site_result", ohdear.CheckResult(st.site.SummarizedChecksResult), siteLabels(st.site))
+	}
+
+	mw.family("ohdear_site_last_run_age_seconds", gaugeType, "Seconds since the checks of a site last ran.")
+	for _, st := range c.sites {
+		if st.site.LatestRunDate != nil {
+			mw.sample("ohdear_site_last_run_age_seconds", now.Sub(st.site.LatestRunDate.Time).Seconds(), siteLabels(st.site)...)
+		}
+	}
+
+	mw.family("ohdear_check_enabled", gaugeType, "Whether a check is enabled on a site.")
+	for _, st := range c.sites {
+		for _, ck := range st.site.Checks {
+			mw.sample("ohdear_check_enabled", boolValue(ck.Enabled), checkLabels(st.site, ck)...)
+		}
+	}
+
+	mw.family("ohdear_check_result", gaugeType, "Result of the latest run of a check, 1 for the current result.")
+	for _, st := range c.sites {
+		for _, ck := range st.site.Checks {
+			if ck.LatestRunResult != "" {
+				writeResult(mw, "ohdear_check_result", ck.LatestRunResult, checkLabels(st.site, ck))
+			}
+		}
+	}
+
+	mw.family("ohdear_check_last_run_age_seconds", gaugeType, "Seconds since the latest run of a check ended.")
+	for _, st := range c.sites {
+		for _, ck := range st.site.Checks {
+			if ck.LatestRunEndedAt != nil {
+				mw.sample("ohdear_check_last_run_age_seconds", now.Sub(ck.LatestRunEndedAt.Time).Seconds(), checkLabels(st.site, ck)...)
+			}
+		}
+	}
+
+	mw.family("ohdear_site_uptime_percent", gaugeType, "Uptime percentage of a site over the window.")
+	for _, st := range c.sites {
+		for _, d := range c.opts.Windows {
+			if v, ok := st.uptime[d]; ok {
+				mw.sample("ohdear_site_uptime_percent", v, siteLabels(st.site, label{"window", formatWindow(d)})...)
+			}
+		}
+	}
+
+	mw.family("ohdear_certificate_days_until_expiry", gaugeType, "Days left before the certificate of a site expires.")
+	for _, st := range c.sites {
+		ch := st.certificate
+		if ch != nil && ch.Details != nil && ch.Details.ValidUntil != nil {
+			days := ch.Details.ValidUntil.Sub(now).Hours() / 24
+			mw.sample("ohdear_certificate_days_until_expiry", days, siteLabels(st.site, label{"issuer", ch.Details.Issuer})...)
+		}
+	}
+
+	mw.family("ohdear_scrape_errors", counterType, "Failed calls to the oh-dear API by operation.")
+	for _, op := range operations {
+		mw.sample("ohdear_scrape_errors_total", c.errors[op], label{"operation", op})
+	}
+
+	mw.family("ohdear_last_refresh_timestamp_seconds", gaugeType, "Time of the latest successful refresh of the cache.")
+	if !c.lastSuccess.IsZero() {
+		mw.sample("ohdear_last_refresh_timestamp_seconds", float64(c.lastSuccess.UnixNano())/1e9)
+	}
+
+	mw.family("ohdear_last_refresh_duration_seconds", gaugeType, "Duration of the latest successful refresh of the cache.")
+	if !c.lastSuccess.IsZero() {
+		mw.sample("ohdear_last_refresh_duration_seconds", c.lastRefresh.Seconds())
+	}
+
+	return mw.flush()
+}
+
+// writeResult writes one sample per known result, unknown results
+// get their own sample so they are not lost.
+func writeResult(mw *metricsWriter, name string, current ohdear.CheckResult, labels []label) {
+	known := false
+	for _, r := range ohdear.CheckResults {
+		known = known || r == current
+		mw.sample(name, boolValue(r == current), append(labels, label{"result", string(r)})...)
+	}
+
+	if !known && current != "" {
+		mw.sample(name, 1, append(labels, label{"result", string(current)})...)
+	}
+}
+
+func siteLabels(s *ohdear.Site, extra ...label) []label {
+	labels := []label{
+		{"site_id", strconv.FormatUint(uint64(s.ID), 10)},
+		{"url", s.Target()},
+	}
+
+	return append(labels, extra...)
+}
+
+func checkLabels(s *ohdear.Site, ck *ohdear.SiteCheck) []label {
+	return siteLabels(s,
+		label{"check_id", strconv.FormatUint(uint64(ck.ID), 10)},
+		label{"check_type", string(ck.Type)},
+	)
+}
+
+// formatWindow names a window using days or hours when possible.
+func formatWindow(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+
+	return d.String()
+}
+
+// parseWindows parses a comma separated list of windows, days
+// are accepted with the d suffix on top of the time.Duration units.
+func parseWindows(s string) (windows []time.Duration, err error) {
+	for _, v := range splitList(s) {
+		var d time.Duration
+		if n, perr := strconv.ParseUint(v[:len(v)-1], 10, 0); perr == nil && v[len(v)-1] == 'd' {
+			d = time.Duration(n) * 24 * time.Hour
+		} else if d, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid window %q", v)
+		}
+
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid window %q", v)
+		}
+
+		windows = append(windows, d)
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+
+	return
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func setupCollector(t *testing.T, opts options, now time.Time) (mux *http.ServeMux, c *collector, teardown func()) {
+	mux = http.NewServeMux()
+	srv := httptest.NewServer(mux)
+
+	client, err := ohdear.NewClientWithTokenSource(nil, srv.URL+"/", ohdear.StaticTokenSource("exporter_token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c = newCollector(client, opts, log.New(ioutil.Discard, "", 0))
+	c.now = func() time.Time { return now }
+
+	return mux, c, srv.Close
+}
+
+func scrape(t *testing.T, c *collector) string {
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, openMetricsContentType, rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasSuffix(rec.Body.String(), "# EOF\n"))
+
+	return rec.Body.String()
+}
+
+func TestCollector_SitesAndCertificates(t *testing.T) {
+	now := time.Date(2019, 9, 16, 8, 0, 0, 0, time.UTC)
+	mux, c, teardown := setupCollector(t, options{PageSize: 50, TeamID: 1, CertificateInterval: time.Hour}, now)
+	defer teardown()
+
+	var listed, certificates int
+	mux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		listed++
+		assert.Equal(t, "Bearer exporter_token", r.Header.Get(ohdear.AuthHeader))
+		assert.Equal(t, "1", r.URL.Query().Get("filter[team_id]"))
+		assert.Equal(t, "1", r.URL.Query().Get("page[number]"))
+		_, _ = fmt.Fprint(w, testdata.MultipleSitesResponse)
+	})
+	mux.HandleFunc("/certificate-health/2", func(w http.ResponseWriter, r *http.Request) {
+		certificates++
+		_, _ = fmt.Fprint(w, testdata.CertificateHealthResponse)
+	})
+
+	assert.Nil(t, c.refresh(context.Background()))
+	assert.Nil(t, c.refresh(context.Background()))
+	assert.Equal(t, 2, listed)
+	assert.Equal(t, 1, certificates, "the certificate health is cached")
+
+	out := scrape(t, c)
+	assert.Equal(t, 2, listed, "scrapes are served from the cache")
+
+	for _, want := range []string{
+		"# TYPE ohdear_site_info gauge\n",
+		`ohdear_site_info{site_id="1",url="http://yoursite.tld",label="your-site",team_id="1",monitor_type="http"} 1` + "\n",
+		`ohdear_site_result{site_id="2",url="https://yourothersite.tld",result="failed"} 1` + "\n",
+		`ohdear_site_result{site_id="2",url="https://yourothersite.tld",result="succeeded"} 0` + "\n",
+		`ohdear_site_last_run_age_seconds{site_id="1",url="http://yoursite.tld"} 1858` + "\n",
+		`ohdear_check_enabled{site_id="2",url="https://yourothersite.tld",check_id="5",check_type="certificate_transparency"} 1` + "\n",
+		`ohdear_check_result{site_id="2",url="https://yourothersite.tld",check_id="2",check_type="broken_links",result="failed"} 1` + "\n",
+		`ohdear_check_last_run_age_seconds{site_id="2",url="https://yourothersite.tld",check_id="2",check_type="broken_links"} 1855` + "\n",
+		`ohdear_certificate_days_until_expiry{site_id="2",url="https://yourothersite.tld",issuer="Let's Encrypt Authority X3"} 71.28`,
+		"# TYPE ohdear_scrape_errors counter\n",
+		`ohdear_scrape_errors_total{operation="list_sites"} 0` + "\n",
+		"ohdear_last_refresh_timestamp_seconds 1.5686208e+09\n",
+	} {
+		assert.Contains(t, out, want)
+	}
+
+	assert.NotContains(t, out, `check_type="certificate_transparency",result=`, "checks without runs have no result")
+	assert.NotContains(t, out, "ohdear_site_uptime_percent{", "uptime windows are disabled")
+}
+
+func TestCollector_Uptime(t *testing.T) {
+	now := time.Date(2018, 9, 24, 0, 0, 0, 0, time.UTC)
+	mux, c, teardown := setupCollector(t, options{
+		Windows:        []time.Duration{24 * time.Hour, 7 * 24 * time.Hour},
+		UptimeInterval: time.Hour,
+	}, now)
+	defer teardown()
+
+	mux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testdata.MultipleSitesResponse)
+	})
+	mux.HandleFunc("/certificate-health/2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	splits := make(map[string]int)
+	for _, id := range []string{"1", "2"} {
+		mux.HandleFunc("/sites/"+id+"/uptime", func(w http.ResponseWriter, r *http.Request) {
+			splits[r.URL.Query().Get("split")]++
+			_, _ = fmt.Fprint(w, testdata.UptimeResponse)
+		})
+	}
+
+	assert.Nil(t, c.refresh(context.Background()))
+	assert.Equal(t, map[string]int{"hour": 2, "day": 2}, splits)
+
+	out := scrape(t, c)
+	for _, want := range []string{
+		`ohdear_site_uptime_percent{site_id="1",url="http://yoursite.tld",window="1d"} 98` + "\n",
+		`ohdear_site_uptime_percent{site_id="2",url="https://yourothersite.tld",window="7d"} 99.32`,
+		`ohdear_scrape_errors_total{operation="certificate_health"} 1` + "\n",
+	} {
+		assert.Contains(t, out, want)
+	}
+}
+
+func TestCollector_ListFailureKeepsCache(t *testing.T) {
+	now := time.Date(2019, 9, 16, 8, 0, 0, 0, time.UTC)
+	mux, c, teardown := setupCollector(t, options{PageSize: 2}, now)
+	defer teardown()
+
+	fail := false
+	var pages []string
+	mux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		page := r.URL.Query().Get("page[number]")
+		pages = append(pages, page)
+		if page != "1" {
+			_, _ = fmt.Fprint(w, testdata.SitesLastPageResponse)
+			return
+		}
+		_, _ = fmt.Fprint(w, testdata.SitesFirstPageResponse)
+	})
+	mux.HandleFunc("/certificate-health/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testdata.CertificateHealthResponse)
+	})
+
+	assert.Nil(t, c.refresh(context.Background()))
+	assert.Equal(t, []string{"1", "2"}, pages, "the last page reported by the meta ends the listing")
+
+	fail = true
+	assert.NotNil(t, c.refresh(context.Background()))
+
+	out := scrape(t, c)
+	assert.Contains(t, out, `ohdear_scrape_errors_total{operation="list_sites"} 1`+"\n")
+	assert.Contains(t, out, `ohdear_site_info{site_id="2"`)
+	assert.Contains(t, out, `ohdear_site_info{site_id="3"`)
+}
+
+func TestMetricsWriter_Escaping(t *testing.T) {
+	var buf bytes.Buffer
+	mw := newMetricsWriter(&buf)
+	mw.family("test_metric", gaugeType, "Help with a \\ and\na new line.")
+	mw.sample("test_metric", 0.5, label{"value", "a \"quoted\" \\ value\n"})
+
+	assert.Nil(t, mw.flush())
+	assert.Equal(t, "# TYPE test_metric gauge\n"+
+		"# HELP test_metric Help with a \\\\ and\\na new line.\n"+
+		"test_metric{value=\"a \\\"quoted\\\" \\\\ value\\n\"} 0.5\n"+
+		"# EOF\n", buf.String())
+}
+
+func TestParseWindows(t *testing.T) {
+	got, err := parseWindows("30d, 1d,12h")
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{12 * time.Hour, 24 * time.Hour, 30 * 24 * time.Hour}, got)
+	assert.Equal(t, "12h", formatWindow(got[0]))
+	assert.Equal(t, "30d", formatWindow(got[2]))
+	assert.Equal(t, "1m30s", formatWindow(90*time.Second))
+
+	got, err = parseWindows("")
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+
+	for _, in := range []string{"week", "-1h", "0d"} {
+		_, err = parseWindows(in)
+		assert.NotNil(t, err, in)
+	}
+}
@@ -0,0 +1,148 @@
+// Command ohdear-exporter exposes the status of oh-dear sites as
+// OpenMetrics for Prometheus.
+//
+// Usage:
+//
+//	ohdear-exporter [flags]
+//
+// The sites are listed in the background every --interval, the uptime
+// percentages and the certificate health are refreshed when older than
+// --uptime-interval and --certificate-interval. Scrapes of /metrics are
+// served from the cache so they never reach the oh-dear rate limit, the
+// API calls are further spread using --requests-per-minute.
+//
+// The API token is read from the OHDEAR_API_TOKEN environment variable
+// or from --token-file, which is read again when it changes.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+// Process exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	var (
+		opts      options
+		listen    string
+		baseURL   string
+		tokenFile string
+		windows   string
+	)
+
+	fs := flag.NewFlagSet("ohdear-exporter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&listen, "listen", ":9785", "address serving the metrics")
+	fs.StringVar(&baseURL, "base-url", "", "oh-dear API base url")
+	fs.StringVar(&tokenFile, "token-file", "", "file containing the API token, defaults to $"+ohdear.APITokenEnv)
+	fs.UintVar(&opts.TeamID, "team", 0, "only export the sites of the team")
+	fs.UintVar(&opts.PageSize, "page-size", 50, "amount of sites requested per page")
+	fs.DurationVar(&opts.Interval, "interval", time.Minute, "interval between two refreshes of the sites")
+	fs.DurationVar(&opts.UptimeInterval, "uptime-interval", 15*time.Minute, "maximum age of the uptime percentages")
+	fs.DurationVar(&opts.CertificateInterval, "certificate-interval", time.Hour, "maximum age of the certificate health")
+	fs.StringVar(&windows, "uptime-windows", "1d,7d,30d", "comma separated uptime windows, empty to disable")
+	fs.IntVar(&opts.RequestsPerMinute, "requests-per-minute", 30, "maximum API calls per minute, 0 disables the limit")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	var err error
+	if opts.Windows, err = parseWindows(windows); err != nil {
+		fmt.Fprintf(stderr, "ohdear-exporter: %v\n", err)
+		return exitUsage
+	}
+
+	if opts.Interval <= 0 || opts.RequestsPerMinute < 0 {
+		fmt.Fprintln(stderr, "ohdear-exporter: --interval must be positive and --requests-per-minute can not be negative")
+		return exitUsage
+	}
+
+	client, err := newClient(baseURL, tokenFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "ohdear-exporter: %v\n", err)
+		return exitError
+	}
+
+	logger := log.New(stderr, "ohdear-exporter: ", log.LstdFlags)
+	c := newCollector(client, opts, logger)
+	go c.run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c)
+	srv := &http.Server{Addr: listen, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	logger.Printf("serving metrics on %s/metrics", listen)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Print(err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// newClient builds the API client, the token file takes
+// precedence over the environment.
+func newClient(baseURL, tokenFile string) (*ohdear.Client, error) {
+	if baseURL != "" && !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	if tokenFile != "" {
+		return ohdear.NewClientWithTokenSource(nil, baseURL, ohdear.NewFileTokenSource(tokenFile))
+	}
+
+	return ohdear.NewClient(nil, baseURL, "")
+}
+
+func splitList(s string) (out []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// openMetricsContentType is the media type of the exposition format.
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Metric family types.
+const (
+	gaugeType   = "gauge"
+	counterType = "counter"
+)
+
+// label is a metric label name and value pair.
+type label struct {
+	name  string
+	value string
+}
+
+// metricsWriter writes metric families using the OpenMetrics text
+// format, the first write error is kept and returned by flush.
+type metricsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func newMetricsWriter(w io.Writer) *metricsWriter {
+	return &metricsWriter{w: bufio.NewWriter(w)}
+}
+
+// family writes the metadata of a metric family, it must be called
+// before writing the samples of the family.
+func (mw *metricsWriter) family(name, typ, help string) {
+	mw.write("# TYPE ", name, " ", typ, "\n")
+	mw.write("# HELP ", name, " ", escapeHelp(help), "\n")
+}
+
+// sample writes a single sample, counters must use the _total suffix.
+func (mw *metricsWriter) sample(name string, v float64, labels ...label) {
+	mw.write(name)
+	if len(labels) > 0 {
+		mw.write("{")
+		for i, l := range labels {
+			if i > 0 {
+				mw.write(",")
+			}
+			mw.write(l.name, `="`, escapeLabelValue(l.value), `"`)
+		}
+		mw.write("}")
+	}
+	mw.write(" ", formatValue(v), "\n")
+}
+
+// flush terminates the exposition and flushes the buffered output.
+func (mw *metricsWriter) flush() error {
+	mw.write("# EOF\n")
+	if mw.err != nil {
+		return mw.err
+	}
+
+	return mw.w.Flush()
+}
+
+func (mw *metricsWriter) write(parts ...string) {
+	for _, p := range parts {
+		if mw.err != nil {
+			return
+		}
+		_, mw.err = mw.w.WriteString(p)
+	}
+}
+
+var (
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// boolValue converts a boolean into a gauge value.
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
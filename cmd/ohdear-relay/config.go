@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/VictorAvelar/goh-dear/ohdear/webhook"
+)
+
+// Configuration defaults.
+const (
+	defaultListen      = ":8080"
+	defaultPath        = "/ohdear"
+	defaultDedupWindow = 10 * time.Minute
+	defaultQueueSize   = 1000
+	defaultAttempts    = 5
+	defaultBackoff     = 10 * time.Second
+	defaultMaxBackoff  = 5 * time.Minute
+	secretEnv          = "OHDEAR_WEBHOOK_SECRET"
+)
+
+// defaultTemplate renders the notifications without a custom template.
+const defaultTemplate = `[{{.Type}}]{{with .Site}} {{if .Label}}{{.Label}} {{end}}{{.URL}}{{end}}{{with .Run}}{{if .Summary}}: {{.Summary}}{{end}}{{end}}`
+
+// config is the content of the relay configuration file.
+type config struct {
+	Listen string `yaml:"listen"`
+	Path   string `yaml:"path"`
+	// Secret verifies the webhook signatures, defaults to $OHDEAR_WEBHOOK_SECRET.
+	Secret string `yaml:"secret"`
+	// Tolerance is the maximum age of the accepted events.
+	Tolerance time.Duration `yaml:"tolerance"`
+	// DedupWindow is the period during which repeated events are dropped.
+	DedupWindow time.Duration  `yaml:"dedup_window"`
+	Retry       retryConfig    `yaml:"retry"`
+	Sinks       []*sinkConfig  `yaml:"sinks"`
+	Routes      []*routeConfig `yaml:"routes"`
+}
+
+// retryConfig controls the redelivery of failed notifications.
+type retryConfig struct {
+	QueueSize  int           `yaml:"queue_size"`
+	Attempts   int           `yaml:"attempts"`
+	Backoff    time.Duration `yaml:"backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+// sinkConfig describes a notification destination.
+type sinkConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// URL is used by the slack, teams and json sinks.
+	URL string `yaml:"url"`
+	// Path is used by the file sink.
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+	// Template renders the message, it is executed with the typed event.
+	Template string `yaml:"template"`
+}
+
+// routeConfig selects the sinks receiving an event, empty
+// filters match every event.
+type routeConfig struct {
+	Events []webhook.EventType `yaml:"events"`
+	// Labels are path.Match patterns matched against the site label.
+	Labels   []string `yaml:"labels"`
+	Teams    []uint   `yaml:"teams"`
+	Sinks    []string `yaml:"sinks"`
+	Template string   `yaml:"template"`
+}
+
+// loadConfig reads the configuration file and applies the defaults.
+func loadConfig(file string) (*config, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := new(config)
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	cfg.setDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return cfg, nil
+}
+
+func (c *config) setDefaults() {
+	if c.Listen == "" {
+		c.Listen = defaultListen
+	}
+
+	if c.Path == "" {
+		c.Path = defaultPath
+	}
+
+	if c.DedupWindow == 0 {
+		c.DedupWindow = defaultDedupWindow
+	}
+
+	if c.Retry.QueueSize <= 0 {
+		c.Retry.QueueSize = defaultQueueSize
+	}
+
+	if c.Retry.Attempts <= 0 {
+		c.Retry.Attempts = defaultAttempts
+	}
+
+	if c.Retry.Backoff <= 0 {
+		c.Retry.Backoff = defaultBackoff
+	}
+
+	if c.Retry.MaxBackoff < c.Retry.Backoff {
+		c.Retry.MaxBackoff = defaultMaxBackoff
+		if c.Retry.MaxBackoff < c.Retry.Backoff {
+			c.Retry.MaxBackoff = c.Retry.Backoff
+		}
+	}
+}
+
+func (c *config) validate() error {
+	if len(c.Sinks) == 0 {
+		return fmt.Errorf("at least one sink is required")
+	}
+
+	names := make(map[string]bool, len(c.Sinks))
+	for i, s := range c.Sinks {
+		if s == nil || s.Name == "" {
+			return fmt.Errorf("sink %d: missing name", i)
+		}
+
+		if names[s.Name] {
+			return fmt.Errorf("sink %q: duplicated name", s.Name)
+		}
+		names[s.Name] = true
+
+		switch s.Type {
+		case slackSink, teamsSink, jsonSink:
+			if s.URL == "" {
+				return fmt.Errorf("sink %q: missing url", s.Name)
+			}
+		case fileSink:
+			if s.Path == "" {
+				return fmt.Errorf("sink %q: missing path", s.Name)
+			}
+		case stdoutSink:
+		default:
+			return fmt.Errorf("sink %q: unknown type %q, expected one of %v", s.Name, s.Type, sinkTypes)
+		}
+
+		if _, err := parseTemplate(s.Template); err != nil {
+			return fmt.Errorf("sink %q: %w", s.Name, err)
+		}
+	}
+
+	if len(c.Routes) == 0 {
+		return fmt.Errorf("at least one route is required")
+	}
+
+	for i, r := range c.Routes {
+		if r == nil || len(r.Sinks) == 0 {
+			return fmt.Errorf("route %d: missing sinks", i)
+		}
+
+		for _, s := range r.Sinks {
+			if !names[s] {
+				return fmt.Errorf("route %d: unknown sink %q", i, s)
+			}
+		}
+
+		for _, l := range r.Labels {
+			if _, err := path.Match(l, ""); err != nil {
+				return fmt.Errorf("route %d: invalid label pattern %q", i, l)
+			}
+		}
+
+		if _, err := parseTemplate(r.Template); err != nil {
+			return fmt.Errorf("route %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// parseTemplate parses a message template, a nil template
+// is returned for empty texts.
+func parseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	return template.New("message").Parse(text)
+}
@@ -0,0 +1,137 @@
+// Command ohdear-relay receives oh-dear webhooks and forwards the
+// events to chat and generic sinks.
+//
+// Usage:
+//
+//	ohdear-relay --config relay.yaml
+//
+// The configuration file declares the sinks and the routes selecting
+// the sinks of every event by event type, site label pattern and team:
+//
+//	secret: your-webhook-secret
+//	sinks:
+//	  - name: ops
+//	    type: slack
+//	    url: https://hooks.slack.com/services/...
+//	    template: '{{.Site.URL}} is down: {{.Reason}}'
+//	  - name: archive
+//	    type: file
+//	    path: /var/log/ohdear.ndjson
+//	routes:
+//	  - events: [uptimeCheckFailed]
+//	    labels: ["prod-*"]
+//	    sinks: [ops]
+//	  - sinks: [archive]
+//
+// Supported sink types are slack, teams, json, stdout and file.
+// Templates use text/template and are executed with the typed event
+// of the ohdear/webhook package.
+//
+// Failed deliveries are retried with an exponential backoff and the
+// same event repeated by a site inside dedup_window is forwarded once.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/VictorAvelar/goh-dear/ohdear/webhook"
+)
+
+// Process exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// deliveryWorkers is the amount of notifications sent in parallel.
+const deliveryWorkers = 4
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ohdear-relay", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "relay.yaml", "path to the configuration file")
+	listen := fs.String("listen", "", "address receiving the webhooks, overrides the configured one")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "ohdear-relay: %v\n", err)
+		return exitError
+	}
+
+	if *listen != "" {
+		cfg.Listen = *listen
+	}
+
+	if cfg.Secret == "" {
+		cfg.Secret = os.Getenv(secretEnv)
+	}
+
+	if cfg.Secret == "" {
+		fmt.Fprintf(stderr, "ohdear-relay: missing webhook secret, set it in the configuration or $%s\n", secretEnv)
+		return exitError
+	}
+
+	logger := log.New(stderr, "ohdear-relay: ", log.LstdFlags)
+	r, err := newRelay(cfg, &http.Client{Timeout: sendTimeout}, stdout, logger)
+	if err != nil {
+		fmt.Fprintf(stderr, "ohdear-relay: %v\n", err)
+		return exitError
+	}
+
+	h := webhook.NewHandler(cfg.Secret)
+	h.Tolerance = cfg.Tolerance
+	h.ErrorLog = logger
+	h.HandleDefault(r.handle)
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path, h)
+	srv := &http.Server{Addr: cfg.Listen, Handler: mux}
+
+	go r.run(ctx, deliveryWorkers)
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	logger.Printf("receiving webhooks on %s%s", cfg.Listen, cfg.Path)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Print(err)
+		return exitError
+	}
+
+	return exitOK
+}
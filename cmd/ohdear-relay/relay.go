@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/VictorAvelar/goh-dear/ohdear/webhook"
+)
+
+// sendTimeout limits every delivery attempt.
+const sendTimeout = 30 * time.Second
+
+var errQueueFull error = fmt.Errorf("the retry queue is full")
+
+// namedSink is a configured sink and its template.
+type namedSink struct {
+	name     string
+	sink     sink
+	template *template.Template
+}
+
+// route is a parsed routing rule.
+type route struct {
+	events   map[webhook.EventType]bool
+	labels   []string
+	teams    map[uint]bool
+	sinks    []string
+	template *template.Template
+}
+
+// delivery is a notification waiting to be sent to a sink.
+type delivery struct {
+	sink    *namedSink
+	n       *notification
+	attempt int
+}
+
+// relay routes the verified webhook events to the sinks.
+type relay struct {
+	sinks    map[string]*namedSink
+	routes   []*route
+	retry    retryConfig
+	window   time.Duration
+	fallback *template.Template
+	logger   *log.Logger
+	now      func() time.Time
+
+	queue chan *delivery
+	// qmu serializes the producers so the free room of the
+	// queue can only grow between checking and filling it.
+	qmu  sync.Mutex
+	mu   sync.Mutex
+	last map[string]*lastEvent
+}
+
+// lastEvent is the latest event of a site for a group of events.
+type lastEvent struct {
+	typ webhook.EventType
+	at  time.Time
+}
+
+func newRelay(cfg *config, client *http.Client, stdout io.Writer, logger *log.Logger) (*relay, error) {
+	r := &relay{
+		sinks:    make(map[string]*namedSink, len(cfg.Sinks)),
+		retry:    cfg.Retry,
+		window:   cfg.DedupWindow,
+		fallback: template.Must(parseTemplate(defaultTemplate)),
+		logger:   logger,
+		now:      time.Now,
+		queue:    make(chan *delivery, cfg.Retry.QueueSize),
+		last:     make(map[string]*lastEvent),
+	}
+
+	for _, sc := range cfg.Sinks {
+		s, err := newSink(sc, client, stdout)
+		if err != nil {
+			return nil, err
+		}
+
+		tpl, err := parseTemplate(sc.Template)
+		if err != nil {
+			return nil, err
+		}
+
+		r.sinks[sc.Name] = &namedSink{name: sc.Name, sink: s, template: tpl}
+	}
+
+	for _, rc := range cfg.Routes {
+		tpl, err := parseTemplate(rc.Template)
+		if err != nil {
+			return nil, err
+		}
+
+		rt := &route{
+			events:   make(map[webhook.EventType]bool, len(rc.Events)),
+			labels:   rc.Labels,
+			teams:    make(map[uint]bool, len(rc.Teams)),
+			sinks:    rc.Sinks,
+			template: tpl,
+		}
+
+		for _, e := range rc.Events {
+			rt.events[e] = true
+		}
+
+		for _, t := range rc.Teams {
+			rt.teams[t] = true
+		}
+
+		r.routes = append(r.routes, rt)
+	}
+
+	return r, nil
+}
+
+// handle routes an event, it is registered as the default
+// handler of the webhook receiver.
+//
+// An error is returned when the queue can not hold the notifications
+// so oh-dear delivers the event again later, in that case none of them
+// is queued so the sinks are not notified twice.
+func (r *relay) handle(ctx context.Context, e webhook.Event) error {
+	key := dedupKey(e)
+	if r.duplicated(key, e.Meta().Type) {
+		r.logger.Printf("dropping repeated %s event of %s", e.Meta().Type, siteName(e))
+		return nil
+	}
+
+	var deliveries []*delivery
+	sent := make(map[string]bool)
+	for _, rt := range r.routes {
+		if !rt.matches(e) {
+			continue
+		}
+
+		for _, name := range rt.sinks {
+			if sent[name] {
+				continue
+			}
+			sent[name] = true
+
+			s := r.sinks[name]
+			n := &notification{Event: e, Message: r.message(e, rt.template, s.template)}
+			deliveries = append(deliveries, &delivery{sink: s, n: n})
+		}
+	}
+
+	if err := r.enqueue(deliveries...); err != nil {
+		r.forget(key)
+		return err
+	}
+
+	return nil
+}
+
+// matches reports whether the event passes all the route filters.
+func (rt *route) matches(e webhook.Event) bool {
+	p := e.Meta()
+	if len(rt.events) > 0 && !rt.events[p.Type] {
+		return false
+	}
+
+	if len(rt.teams) > 0 && (p.Site == nil || !rt.teams[p.Site.TeamID]) {
+		return false
+	}
+
+	if len(rt.labels) == 0 {
+		return true
+	}
+
+	if p.Site == nil {
+		return false
+	}
+
+	for _, l := range rt.labels {
+		if ok, _ := path.Match(l, p.Site.Label); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// message renders the event using the first available template,
+// the default template is used when the custom one fails.
+func (r *relay) message(e webhook.Event, templates ...*template.Template) string {
+	for _, tpl := range templates {
+		if tpl == nil {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, e); err != nil {
+			r.logger.Printf("rendering %s event: %v", e.Meta().Type, err)
+			break
+		}
+
+		return buf.String()
+	}
+
+	var buf bytes.Buffer
+	_ = r.fallback.Execute(&buf, e)
+
+	return buf.String()
+}
+
+// eventGroup relates the events describing the state of the same check,
+// so a recovery lets the next failure through.
+func eventGroup(t webhook.EventType) string {
+	switch t {
+	case webhook.UptimeCheckFailed, webhook.UptimeCheckRecovered:
+		return "uptime"
+	case webhook.BrokenLinksFound, webhook.BrokenLinksFixed:
+		return "broken_links"
+	case webhook.MixedContentFound, webhook.MixedContentFixed:
+		return "mixed_content"
+	case webhook.CertificateExpiresSoon, webhook.CertificateHasChanged, webhook.CertificateUnhealthy, webhook.CertificateFixed:
+		return "certificate"
+	}
+
+	return string(t)
+}
+
+// dedupKey identifies the site and the group of the event.
+func dedupKey(e webhook.Event) string {
+	p := e.Meta()
+	site := ""
+	if p.Site != nil {
+		site = strconv.FormatUint(uint64(p.Site.ID), 10)
+	}
+
+	return site + "/" + eventGroup(p.Type)
+}
+
+// duplicated reports whether the site already sent the same event
+// inside the deduplication window without changing state in between.
+func (r *relay) duplicated(key string, typ webhook.EventType) bool {
+	if r.window < 0 {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for k, l := range r.last {
+		if now.Sub(l.at) >= r.window {
+			delete(r.last, k)
+		}
+	}
+
+	if l, ok := r.last[key]; ok && l.typ == typ {
+		return true
+	}
+
+	r.last[key] = &lastEvent{typ: typ, at: now}
+
+	return false
+}
+
+// forget drops the latest event of a key so a new delivery is accepted.
+func (r *relay) forget(key string) {
+	r.mu.Lock()
+	delete(r.last, key)
+	r.mu.Unlock()
+}
+
+// enqueue queues all the deliveries or none of them.
+func (r *relay) enqueue(deliveries ...*delivery) error {
+	r.qmu.Lock()
+	defer r.qmu.Unlock()
+
+	if cap(r.queue)-len(r.queue) < len(deliveries) {
+		return errQueueFull
+	}
+
+	for _, d := range deliveries {
+		r.queue <- d
+	}
+
+	return nil
+}
+
+// run delivers the queued notifications until the context is cancelled.
+func (r *relay) run(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case d := <-r.queue:
+					r.deliver(ctx, d)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// deliver sends a notification and schedules a new attempt
+// with an exponential backoff when it fails.
+func (r *relay) deliver(ctx context.Context, d *delivery) {
+	sctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := d.sink.sink.send(sctx, d.n)
+	cancel()
+	if err == nil {
+		return
+	}
+
+	d.attempt++
+	if d.attempt >= r.retry.Attempts {
+		r.logger.Printf("giving up %s event for sink %s after %d attempts: %v", d.n.Event.Meta().Type, d.sink.name, d.attempt, err)
+		return
+	}
+
+	wait := r.backoff(d.attempt)
+	r.logger.Printf("sink %s failed, retrying in %s: %v", d.sink.name, wait, err)
+
+	time.AfterFunc(wait, func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := r.enqueue(d); err != nil {
+			r.logger.Printf("dropping %s event for sink %s: %v", d.n.Event.Meta().Type, d.sink.name, err)
+		}
+	})
+}
+
+func (r *relay) backoff(attempt int) time.Duration {
+	d := r.retry.Backoff
+	for i := 1; i < attempt && d < r.retry.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > r.retry.MaxBackoff {
+		d = r.retry.MaxBackoff
+	}
+
+	return d
+}
+
+func siteName(e webhook.Event) string {
+	if s := e.Meta().Site; s != nil {
+		return s.Target()
+	}
+
+	return "unknown site"
+}
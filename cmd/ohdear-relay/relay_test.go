@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/ohdear/webhook"
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func writeConfig(t *testing.T, content string) (file string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "ohdear-relay")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file = filepath.Join(dir, "relay.yaml")
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return file, func() { os.RemoveAll(dir) }
+}
+
+func parseEvent(t *testing.T, body string) webhook.Event {
+	e, err := webhook.ParseEvent([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
+
+// capture collects the requests received by a fake sink endpoint.
+type capture struct {
+	mu     sync.Mutex
+	bodies []map[string]interface{}
+	fail   int
+}
+
+func (c *capture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fail > 0 {
+		c.fail--
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	c.bodies = append(c.bodies, body)
+}
+
+func (c *capture) received() []map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]map[string]interface{}(nil), c.bodies...)
+}
+
+func TestLoadConfig(t *testing.T) {
+	file, cleanup := writeConfig(t, `
+secret: s3cr3t
+dedup_window: 1m
+retry:
+  attempts: 3
+  backoff: 2s
+sinks:
+  - name: ops
+    type: slack
+    url: https://hooks.slack.test/ops
+routes:
+  - events: [uptimeCheckFailed]
+    labels: ["prod-*"]
+    sinks: [ops]
+`)
+	defer cleanup()
+
+	cfg, err := loadConfig(file)
+	assert.Nil(t, err)
+	assert.Equal(t, defaultListen, cfg.Listen)
+	assert.Equal(t, defaultPath, cfg.Path)
+	assert.Equal(t, time.Minute, cfg.DedupWindow)
+	assert.Equal(t, 3, cfg.Retry.Attempts)
+	assert.Equal(t, 2*time.Second, cfg.Retry.Backoff)
+	assert.Equal(t, defaultMaxBackoff, cfg.Retry.MaxBackoff)
+	assert.Equal(t, []webhook.EventType{webhook.UptimeCheckFailed}, cfg.Routes[0].Events)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"no sinks", "routes: [{sinks: [a]}]", "at least one sink is required"},
+		{"unknown type", "sinks: [{name: a, type: pager}]\nroutes: [{sinks: [a]}]", `sink "a": unknown type "pager"`},
+		{"missing url", "sinks: [{name: a, type: teams}]\nroutes: [{sinks: [a]}]", `sink "a": missing url`},
+		{"missing path", "sinks: [{name: a, type: file}]\nroutes: [{sinks: [a]}]", `sink "a": missing path`},
+		{"duplicated sink", "sinks: [{name: a, type: stdout}, {name: a, type: stdout}]\nroutes: [{sinks: [a]}]", `sink "a": duplicated name`},
+		{"no routes", "sinks: [{name: a, type: stdout}]", "at least one route is required"},
+		{"unknown sink", "sinks: [{name: a, type: stdout}]\nroutes: [{sinks: [b]}]", `route 0: unknown sink "b"`},
+		{"invalid label", "sinks: [{name: a, type: stdout}]\nroutes: [{sinks: [a], labels: ['[']}]", `route 0: invalid label pattern "["`},
+		{"invalid template", "sinks: [{name: a, type: stdout, template: '{{.Site'}]\nroutes: [{sinks: [a]}]", `sink "a": template`},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			file, cleanup := writeConfig(t, c.content)
+			defer cleanup()
+
+			_, err := loadConfig(file)
+			if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
+
+func TestRelay_Routing(t *testing.T) {
+	var stdout bytes.Buffer
+	r, err := newRelay(&config{
+		DedupWindow: time.Minute,
+		Retry:       retryConfig{QueueSize: 10, Attempts: 1},
+		Sinks: []*sinkConfig{
+			{Name: "console", Type: stdoutSink, Template: "{{.Type}} {{.Site.URL}}"},
+			{Name: "archive", Type: stdoutSink},
+		},
+		Routes: []*routeConfig{
+			{Events: []webhook.EventType{webhook.UptimeCheckFailed}, Labels: []string{"your-*"}, Sinks: []string{"console"}, Template: "{{.Site.URL}} is down: {{.Reason}}"},
+			{Teams: []uint{1}, Sinks: []string{"console", "archive"}},
+			{Teams: []uint{2}, Sinks: []string{"archive"}},
+		},
+	}, http.DefaultClient, &stdout, log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, r.handle(context.Background(), parseEvent(t, testdata.UptimeCheckFailedWebhook)))
+	assert.Len(t, r.queue, 2, "a sink matched by several routes is notified once")
+
+	d := <-r.queue
+	assert.Equal(t, "console", d.sink.name)
+	assert.Equal(t, "http://yoursite.tld is down: Connection timed out after 5 seconds", d.n.Message)
+
+	d = <-r.queue
+	assert.Equal(t, "archive", d.sink.name)
+	assert.Equal(t, "[uptimeCheckFailed] your-site http://yoursite.tld: The site is down", d.n.Message)
+
+	assert.Nil(t, r.handle(context.Background(), parseEvent(t, testdata.BrokenLinksFoundWebhook)))
+	assert.Len(t, r.queue, 2)
+	d = <-r.queue
+	assert.Equal(t, "brokenLinksFound https://yourothersite.tld", d.n.Message)
+	<-r.queue
+
+	assert.Nil(t, r.handle(context.Background(), parseEvent(t, testdata.UnknownWebhook)))
+	assert.Len(t, r.queue, 0, "events without matching routes are dropped")
+}
+
+func TestRelay_Deduplication(t *testing.T) {
+	now := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	r, err := newRelay(&config{
+		DedupWindow: 10 * time.Minute,
+		Retry:       retryConfig{QueueSize: 10, Attempts: 1},
+		Sinks:       []*sinkConfig{{Name: "console", Type: stdoutSink}},
+		Routes:      []*routeConfig{{Sinks: []string{"console"}}},
+	}, http.DefaultClient, ioutil.Discard, log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = func() time.Time { return now }
+
+	failed := parseEvent(t, testdata.UptimeCheckFailedWebhook)
+	recovered := parseEvent(t, testdata.UptimeCheckRecoveredWebhook)
+
+	for _, e := range []webhook.Event{failed, failed, recovered, failed} {
+		assert.Nil(t, r.handle(context.Background(), e))
+	}
+	assert.Len(t, r.queue, 3, "repeated events are dropped until the state changes")
+
+	now = now.Add(10 * time.Minute)
+	assert.Nil(t, r.handle(context.Background(), failed))
+	assert.Len(t, r.queue, 4, "events are forwarded again after the window")
+}
+
+func TestRelay_QueueFull(t *testing.T) {
+	r, err := newRelay(&config{
+		Retry:  retryConfig{QueueSize: 2, Attempts: 1},
+		Sinks:  []*sinkConfig{{Name: "a", Type: stdoutSink}, {Name: "b", Type: stdoutSink}},
+		Routes: []*routeConfig{{Sinks: []string{"a", "b"}}},
+	}, http.DefaultClient, ioutil.Discard, log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, r.handle(context.Background(), parseEvent(t, testdata.UptimeCheckFailedWebhook)))
+
+	e := parseEvent(t, testdata.BrokenLinksFoundWebhook)
+	assert.Equal(t, errQueueFull, r.handle(context.Background(), e))
+
+	<-r.queue
+	assert.Equal(t, errQueueFull, r.handle(context.Background(), e))
+	assert.Len(t, r.queue, 1, "events are not queued for some of their sinks")
+
+	<-r.queue
+	assert.Nil(t, r.handle(context.Background(), e))
+	assert.Len(t, r.queue, 2, "rejected events are not deduplicated")
+}
+
+func TestRelay_Retry(t *testing.T) {
+	slack := &capture{fail: 2}
+	srv := httptest.NewServer(slack)
+	defer srv.Close()
+
+	r, err := newRelay(&config{
+		Retry:  retryConfig{QueueSize: 10, Attempts: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond},
+		Sinks:  []*sinkConfig{{Name: "ops", Type: slackSink, URL: srv.URL}},
+		Routes: []*routeConfig{{Sinks: []string{"ops"}}},
+	}, srv.Client(), ioutil.Discard, log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.run(ctx, 1)
+
+	assert.Nil(t, r.handle(ctx, parseEvent(t, testdata.UptimeCheckFailedWebhook)))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(slack.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	got := slack.received()
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, "[uptimeCheckFailed] your-site http://yoursite.tld: The site is down", got[0]["text"])
+	}
+
+	assert.Equal(t, time.Millisecond, r.backoff(1))
+	assert.Equal(t, 2*time.Millisecond, r.backoff(2))
+	assert.Equal(t, 2*time.Millisecond, r.backoff(5))
+}
+
+func TestSinks(t *testing.T) {
+	teams, generic := &capture{}, &capture{}
+	teamsSrv, genericSrv := httptest.NewServer(teams), httptest.NewServer(generic)
+	defer teamsSrv.Close()
+	defer genericSrv.Close()
+
+	dir, err := ioutil.TempDir("", "ohdear-relay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var stdout bytes.Buffer
+	n := &notification{Event: parseEvent(t, testdata.UptimeCheckRecoveredWebhook), Message: "back up"}
+	for _, c := range []*sinkConfig{
+		{Type: teamsSink, URL: teamsSrv.URL},
+		{Type: jsonSink, URL: genericSrv.URL, Headers: map[string]string{"X-Token": "t"}},
+		{Type: stdoutSink},
+		{Type: fileSink, Path: filepath.Join(dir, "events.ndjson")},
+	} {
+		s, err := newSink(c, http.DefaultClient, &stdout)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, s.send(context.Background(), n), c.Type)
+		assert.Nil(t, s.send(context.Background(), n), c.Type)
+	}
+
+	card := teams.received()[0]
+	assert.Equal(t, "MessageCard", card["@type"])
+	assert.Equal(t, "uptimeCheckRecovered", card["title"])
+	assert.Equal(t, "2EB67D", card["themeColor"])
+
+	rec := generic.received()[0]
+	assert.Equal(t, "uptimeCheckRecovered", rec["event"])
+	assert.Equal(t, "back up", rec["message"])
+	assert.NotNil(t, rec["payload"])
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "events.ndjson"))
+	assert.Nil(t, err)
+	assert.Equal(t, stdout.String(), string(b))
+
+	_, err = newSink(&sinkConfig{Type: "pager"}, http.DefaultClient, &stdout)
+	assert.NotNil(t, err)
+
+	failing := &capture{fail: 1}
+	srv := httptest.NewServer(failing)
+	defer srv.Close()
+	s, _ := newSink(&sinkConfig{Type: slackSink, URL: srv.URL}, http.DefaultClient, &stdout)
+	assert.NotNil(t, s.send(context.Background(), n))
+}
+
+func TestRelay_EndToEnd(t *testing.T) {
+	var stdout bytes.Buffer
+	r, err := newRelay(&config{
+		Retry:  retryConfig{QueueSize: 10, Attempts: 1},
+		Sinks:  []*sinkConfig{{Name: "console", Type: stdoutSink}},
+		Routes: []*routeConfig{{Sinks: []string{"console"}}},
+	}, http.DefaultClient, &stdout, log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := webhook.NewHandler("s3cr3t")
+	h.Tolerance = -1
+	h.ErrorLog = log.New(ioutil.Discard, "", 0)
+	h.HandleDefault(r.handle)
+
+	body := testdata.BrokenLinksFoundWebhook
+	req := httptest.NewRequest(http.MethodPost, "/ohdear", strings.NewReader(body))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign([]byte("s3cr3t"), []byte(body)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, r.queue, 1)
+
+	r.deliver(context.Background(), <-r.queue)
+	assert.Contains(t, stdout.String(), `"event":"brokenLinksFound"`)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/VictorAvelar/goh-dear/ohdear/webhook"
+)
+
+// Supported sink types.
+const (
+	slackSink  = "slack"
+	teamsSink  = "teams"
+	jsonSink   = "json"
+	stdoutSink = "stdout"
+	fileSink   = "file"
+)
+
+var sinkTypes = []string{slackSink, teamsSink, jsonSink, stdoutSink, fileSink}
+
+// notification is an event rendered for a sink.
+type notification struct {
+	Event   webhook.Event
+	Message string
+}
+
+// record is the NDJSON and generic JSON representation of a notification.
+type record struct {
+	Event   webhook.EventType `json:"event"`
+	Message string            `json:"message"`
+	Payload json.RawMessage   `json:"payload"`
+}
+
+func newRecord(n *notification) *record {
+	return &record{
+		Event:   n.Event.Meta().Type,
+		Message: n.Message,
+		Payload: n.Event.Meta().Raw,
+	}
+}
+
+// sink delivers notifications to a destination.
+type sink interface {
+	send(ctx context.Context, n *notification) error
+}
+
+// newSink builds the sink described by the configuration.
+func newSink(c *sinkConfig, client *http.Client, stdout io.Writer) (sink, error) {
+	switch c.Type {
+	case slackSink:
+		return &httpSink{client: client, url: c.URL, headers: c.Headers, body: slackBody}, nil
+	case teamsSink:
+		return &httpSink{client: client, url: c.URL, headers: c.Headers, body: teamsBody}, nil
+	case jsonSink:
+		return &httpSink{client: client, url: c.URL, headers: c.Headers, body: jsonBody}, nil
+	case stdoutSink:
+		return &writerSink{w: stdout}, nil
+	case fileSink:
+		return &fileWriterSink{path: c.Path}, nil
+	}
+
+	return nil, fmt.Errorf("unknown sink type %q", c.Type)
+}
+
+// httpSink posts a JSON document built from the notification.
+type httpSink struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+	body    func(n *notification) interface{}
+}
+
+func (s *httpSink) send(ctx context.Context, n *notification) error {
+	b, err := json.Marshal(s.body(n))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s answered %s", s.url, res.Status)
+	}
+
+	return nil
+}
+
+// slackBody builds a Slack compatible incoming webhook message.
+func slackBody(n *notification) interface{} {
+	return map[string]string{"text": n.Message}
+}
+
+// teamsBody builds a Microsoft Teams message card.
+func teamsBody(n *notification) interface{} {
+	p := n.Event.Meta()
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    n.Message,
+		"title":      string(p.Type),
+		"text":       n.Message,
+		"themeColor": themeColor(p.Type),
+	}
+
+	if p.Site != nil && p.Site.URL != "" {
+		card["potentialAction"] = []map[string]interface{}{{
+			"@type":   "OpenUri",
+			"name":    "Open site",
+			"targets": []map[string]string{{"os": "default", "uri": p.Site.URL}},
+		}}
+	}
+
+	return card
+}
+
+// themeColor highlights problems in red and fixes in green.
+func themeColor(t webhook.EventType) string {
+	switch t {
+	case webhook.UptimeCheckRecovered, webhook.BrokenLinksFixed, webhook.MixedContentFixed, webhook.CertificateFixed:
+		return "2EB67D"
+	case webhook.CertificateExpiresSoon, webhook.CertificateHasChanged:
+		return "ECB22E"
+	}
+
+	return "E01E5A"
+}
+
+func jsonBody(n *notification) interface{} {
+	return newRecord(n)
+}
+
+// writerSink writes one JSON document per line.
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *writerSink) send(ctx context.Context, n *notification) error {
+	b, err := json.Marshal(newRecord(n))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(append(b, '\n'))
+
+	return err
+}
+
+// fileWriterSink appends one JSON document per line to a file,
+// the file is opened for every notification so it can be rotated.
+type fileWriterSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileWriterSink) send(ctx context.Context, n *notification) error {
+	b, err := json.Marshal(newRecord(n))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
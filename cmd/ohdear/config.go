@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+	"github.com/VictorAvelar/goh-dear/ohdear/render"
+)
+
+// Configuration related constants.
+const (
+	profileEnv     = "OHDEAR_PROFILE"
+	defaultProfile = "default"
+)
+
+// config is the content of the configuration file.
+type config struct {
+	DefaultProfile string              `yaml:"default_profile,omitempty"`
+	Profiles       map[string]*profile `yaml:"profiles,omitempty"`
+}
+
+// profile groups the settings used to reach an oh-dear team.
+type profile struct {
+	Token        string `yaml:"token,omitempty"`
+	TokenCommand string `yaml:"token_command,omitempty"`
+	BaseURL      string `yaml:"base_url,omitempty"`
+	TeamID       uint   `yaml:"team_id,omitempty"`
+	Output       string `yaml:"output,omitempty"`
+}
+
+// defaultConfigPath returns the location of the configuration file,
+// $XDG_CONFIG_HOME/ohdear/config.yaml when the variable is set and
+// ~/.config/ohdear/config.yaml otherwise.
+func defaultConfigPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "ohdear", "config.yaml")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config", "ohdear", "config.yaml")
+}
+
+// loadConfig reads the configuration file, a missing file
+// results in an empty configuration.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{Profiles: make(map[string]*profile)}
+	if path == "" {
+		return cfg, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*profile)
+	}
+
+	for name, p := range cfg.Profiles {
+		if p == nil {
+			cfg.Profiles[name] = new(profile)
+			continue
+		}
+
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// save writes the configuration file, only the owner can read it
+// as it may contain API tokens.
+func (c *config) save(path string) error {
+	if path == "" {
+		return fmt.Errorf("unable to resolve the configuration file path")
+	}
+
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0600)
+}
+
+// profileName resolves the profile to use, the flag takes precedence
+// over the environment and the configured default profile.
+func (c *config) profileName(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+
+	if env, ok := os.LookupEnv(profileEnv); ok && env != "" {
+		return env
+	}
+
+	if c.DefaultProfile != "" {
+		return c.DefaultProfile
+	}
+
+	return defaultProfile
+}
+
+// profile returns a copy of the named profile, the default profile
+// is allowed to be missing so the token can come from the environment.
+func (c *config) profile(name string) (*profile, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		if name == defaultProfile {
+			return new(profile), nil
+		}
+		return nil, usagef("unknown profile %q", name)
+	}
+
+	cp := *p
+
+	return &cp, nil
+}
+
+func (p *profile) validate() error {
+	if p.Token != "" && p.TokenCommand != "" {
+		return fmt.Errorf("token and token_command are mutually exclusive")
+	}
+
+	if p.TokenCommand != "" && len(strings.Fields(p.TokenCommand)) == 0 {
+		return fmt.Errorf("token_command is empty")
+	}
+
+	if p.Output != "" {
+		if _, err := render.ParseFormat(p.Output); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tokenSource returns the source of the API token of the profile,
+// the token command runs once per process.
+//
+// The command is run with "sh -c", so it can use quoting and pipes.
+func (p *profile) tokenSource() (ohdear.TokenSource, error) {
+	if p.Token != "" || p.TokenCommand == "" {
+		return ohdear.StaticTokenSource(p.Token), nil
+	}
+
+	if strings.TrimSpace(p.TokenCommand) == "" {
+		return nil, fmt.Errorf("token_command is empty")
+	}
+
+	return ohdear.NewCachedTokenSource(ohdear.CommandTokenSource("sh", "-c", p.TokenCommand), 0), nil
+}
+
+// hasToken reports whether the profile configures its own token.
+func (p *profile) hasToken() bool {
+	return p.Token != "" || p.TokenCommand != ""
+}
+
+// client builds an API client for the profile, the token from the
+// environment takes precedence over the configured one.
+func (p *profile) client() (*ohdear.Client, error) {
+	if _, ok := os.LookupEnv(ohdear.APITokenEnv); ok {
+		return ohdear.NewClient(nil, p.baseURL(), "")
+	}
+
+	return p.ownClient()
+}
+
+// ownClient builds an API client using the token of the profile,
+// ignoring the one from the environment.
+func (p *profile) ownClient() (*ohdear.Client, error) {
+	ts, err := p.tokenSource()
+	if err != nil {
+		return nil, err
+	}
+
+	return ohdear.NewClientWithTokenSource(nil, p.baseURL(), ts)
+}
+
+func (p *profile) baseURL() string {
+	if p.BaseURL != "" && !strings.HasSuffix(p.BaseURL, "/") {
+		return p.BaseURL + "/"
+	}
+
+	return p.BaseURL
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+const configUsage = `Config commands:
+  config add <name> [--token token | --token-command cmd] [--base-url url]
+             [--team id] [--output format] [--default]
+  config list
+  config validate [name...]
+`
+
+func (a *app) configCmd(current string, args []string) error {
+	if len(args) == 0 {
+		return usagef("missing config command")
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "add":
+		return a.configAdd(args)
+	case "list":
+		return a.configList(current, args)
+	case "validate":
+		return a.configValidate(current, args)
+	}
+
+	return usagef("unknown config command %q", cmd)
+}
+
+func (a *app) configAdd(args []string) error {
+	var (
+		p          profile
+		setDefault bool
+	)
+
+	fs := a.flagSet("config add <name>")
+	fs.StringVar(&p.Token, "token", "", "API token")
+	fs.StringVar(&p.TokenCommand, "token-command", "", "command printing the API token, e.g. \"pass show ohdear\"")
+	fs.StringVar(&p.BaseURL, "base-url", "", "oh-dear API base url")
+	fs.UintVar(&p.TeamID, "team", 0, "default team id")
+	fs.StringVar(&p.Output, "output", "", "default output format")
+	fs.BoolVar(&setDefault, "default", false, "use the profile by default")
+
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if err := p.validate(); err != nil {
+		return usagef("%v", err)
+	}
+
+	name := pos[0]
+	a.config.Profiles[name] = &p
+	if setDefault || len(a.config.Profiles) == 1 {
+		a.config.DefaultProfile = name
+	}
+
+	if err := a.config.save(a.configPath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "profile %q saved to %s\n", name, a.configPath)
+
+	return nil
+}
+
+func (a *app) configList(current string, args []string) error {
+	if _, err := parseArgs(a.flagSet("config list"), args, 0); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CURRENT\tNAME\tTOKEN\tBASE URL\tTEAM\tOUTPUT")
+	for _, name := range a.profileNames() {
+		p := a.config.Profiles[name]
+
+		mark := ""
+		if name == current {
+			mark = "*"
+		}
+
+		token := "-"
+		switch {
+		case p.Token != "":
+			token = "static"
+		case p.TokenCommand != "":
+			token = "command"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			mark, name, token, orDash(p.BaseURL), orDash(uintString(p.TeamID)), orDash(p.Output))
+	}
+
+	return tw.Flush()
+}
+
+// configValidate asks who owns the token of each profile
+// and reports the first failure.
+func (a *app) configValidate(current string, args []string) (err error) {
+	names, err := parseArgs(a.flagSet("config validate [name...]"), args, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		names = []string{current}
+	}
+
+	for _, name := range names {
+		user, verr := a.validateProfile(name)
+		if verr != nil {
+			fmt.Fprintf(a.stdout, "%s: %v\n", name, verr)
+			if err == nil {
+				err = fmt.Errorf("profile %q is invalid: %w", name, verr)
+			}
+			continue
+		}
+
+		fmt.Fprintf(a.stdout, "%s: ok, authenticated as %s\n", name, user.Email)
+	}
+
+	return err
+}
+
+// validateProfile checks the token configured in the profile, the
+// environment token is only used by profiles without their own token.
+func (a *app) validateProfile(name string) (*ohdear.User, error) {
+	p, err := a.config.profile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	newClient := p.client
+	if p.hasToken() {
+		newClient = p.ownClient
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Me()
+}
+
+func (a *app) profileNames() []string {
+	names := make([]string, 0, len(a.config.Profiles))
+	for name := range a.config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func uintString(v uint) string {
+	if v == 0 {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
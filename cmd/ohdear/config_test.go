@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestRun_ConfigAddAndList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ohdear-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := filepath.Join(dir, "nested", "config.yaml")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "config", "add", "acme", "--token", "secret", "--team", "3"}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "config", "add", "globex", "--token-command", "echo other", "--output", "json", "--default"}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, exitUsage, run([]string{"--config", cfg, "config", "add", "broken", "--output", "xml"}, &stdout, &stderr))
+
+	info, err := os.Stat(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := loadConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "globex", loaded.DefaultProfile)
+	assert.Equal(t, &profile{Token: "secret", TeamID: 3}, loaded.Profiles["acme"])
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "--profile", "acme", "config", "list"}, &stdout, &stderr))
+	assert.Equal(t, "CURRENT  NAME    TOKEN    BASE URL  TEAM  OUTPUT\n"+
+		"*        acme    static   -         3     -\n"+
+		"         globex  command  -         -     json\n", stdout.String())
+}
+
+func TestRun_ProfileSelection(t *testing.T) {
+	mux, _, url, teardown := setupServer(t)
+	defer teardown()
+
+	var (
+		tokens []string
+		teams  []string
+	)
+	mux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get(ohdear.AuthHeader))
+		teams = append(teams, r.URL.Query().Get("filter[team_id]"))
+		_, _ = fmt.Fprint(w, testdata.MultipleSitesResponse)
+	})
+
+	dir, err := ioutil.TempDir("", "ohdear-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := filepath.Join(dir, "config.yaml")
+	content := fmt.Sprintf(`default_profile: acme
+profiles:
+  acme:
+    token: acme_token
+    base_url: %[1]s
+    team_id: 7
+    output: csv
+  globex:
+    token_command: printf '%%s\n' "globex_token"
+    base_url: %[1]s
+`, url)
+	if err := ioutil.WriteFile(cfg, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "sites", "list", "--columns", "id"}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "id\n1\n2\n", stdout.String())
+
+	os.Setenv(profileEnv, "globex")
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "sites", "list"}, &stdout, &stderr), stderr.String())
+	os.Unsetenv(profileEnv)
+
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "--profile", "globex", "sites", "list", "--team", "9"}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, exitUsage, run([]string{"--config", cfg, "--profile", "initech", "sites", "list"}, &stdout, &stderr))
+
+	assert.Equal(t, []string{"Bearer acme_token", "Bearer globex_token", "Bearer globex_token"}, tokens)
+	assert.Equal(t, []string{"7", "", "9"}, teams)
+}
+
+func TestRun_ConfigValidate(t *testing.T) {
+	mux, _, url, teardown := setupServer(t)
+	defer teardown()
+
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(ohdear.AuthHeader) != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprint(w, testdata.MeResponse)
+	})
+
+	dir, err := ioutil.TempDir("", "ohdear-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := filepath.Join(dir, "config.yaml")
+	content := fmt.Sprintf("profiles:\n  good:\n    token: good\n    base_url: %[1]s\n  bad:\n    token: bad\n    base_url: %[1]s\n", url)
+	if err := ioutil.WriteFile(cfg, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "--profile", "good", "config", "validate"}, &stdout, &stderr))
+	assert.Equal(t, "good: ok, authenticated as jane@yoursite.tld\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitUnauthorized, run([]string{"--config", cfg, "config", "validate", "good", "bad"}, &stdout, &stderr))
+	assert.Equal(t, "good: ok, authenticated as jane@yoursite.tld\nbad: response failed with status 401|401 Unauthorized\n", stdout.String())
+
+	_ = os.Setenv(ohdear.APITokenEnv, "good")
+	defer os.Unsetenv(ohdear.APITokenEnv)
+
+	stdout.Reset()
+	assert.Equal(t, exitUnauthorized, run([]string{"--config", cfg, "config", "validate", "bad"}, &stdout, &stderr))
+	assert.Equal(t, "bad: response failed with status 401|401 Unauthorized\n", stdout.String())
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ohdear-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(cfg, []byte("profiles:\n  x:\n    token: a\n    token_command: echo b\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = loadConfig(cfg)
+	assert.Error(t, err)
+
+	if err := ioutil.WriteFile(cfg, []byte("profiles:\n  x:\n    token_command: \"  \"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = loadConfig(cfg)
+	assert.EqualError(t, err, cfg+`: profile "x": token_command is empty`)
+
+	empty, err := loadConfig(filepath.Join(dir, "missing.yaml"))
+	assert.Nil(t, err)
+	assert.Empty(t, empty.Profiles)
+}
+
+func TestDefaultConfigPath(t *testing.T) {
+	xdg, ok := os.LookupEnv("XDG_CONFIG_HOME")
+	home := os.Getenv("HOME")
+	defer func() {
+		os.Setenv("HOME", home)
+		if ok {
+			os.Setenv("XDG_CONFIG_HOME", xdg)
+		} else {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		}
+	}()
+
+	os.Setenv("HOME", "/home/acme")
+	os.Unsetenv("XDG_CONFIG_HOME")
+	assert.Equal(t, filepath.Join("/home/acme", ".config", "ohdear", "config.yaml"), defaultConfigPath())
+
+	os.Setenv("XDG_CONFIG_HOME", "relative")
+	assert.Equal(t, filepath.Join("/home/acme", ".config", "ohdear", "config.yaml"), defaultConfigPath())
+
+	os.Setenv("XDG_CONFIG_HOME", "/xdg")
+	assert.Equal(t, filepath.Join("/xdg", "ohdear", "config.yaml"), defaultConfigPath())
+}
@@ -0,0 +1,192 @@
+// Command ohdear manages oh-dear sites from the command line.
+//
+// Usage:
+//
+//	ohdear [global flags] sites <command> [flags] [arguments]
+//	ohdear [global flags] teams <command> [flags] [arguments]
+//	ohdear [global flags] config <command> [flags] [arguments]
+//
+// The configuration file, by default located at
+// $XDG_CONFIG_HOME/ohdear/config.yaml or ~/.config/ohdear/config.yaml,
+// contains named profiles with their token or token command, base url,
+// default team and output format. Token commands are run with "sh -c":
+//
+//	default_profile: acme
+//	profiles:
+//	  acme:
+//	    token_command: pass show ohdear/acme
+//	    team_id: 1
+//	    output: table
+//
+// The profile is selected with the --profile flag, the OHDEAR_PROFILE
+// environment variable or the default_profile setting. The
+// OHDEAR_API_TOKEN environment variable takes precedence over the
+// token of any profile.
+//
+// Failed API calls exit with a status code derived from the HTTP
+// response, see the exit* constants.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+// Process exit codes.
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitUnauthorized = 3
+	exitNotFound     = 4
+	exitInvalid      = 5
+	exitRateLimited  = 6
+	exitServerError  = 7
+)
+
+// usageError is returned when the command line arguments are invalid.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// app holds the dependencies shared by every command.
+type app struct {
+	stdout     io.Writer
+	stderr     io.Writer
+	configPath string
+	config     *config
+	profile    *profile
+	client     *ohdear.Client
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ohdear", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", defaultConfigPath(), "path to the configuration file")
+	profileName := fs.String("profile", "", "configuration profile, defaults to $"+profileEnv)
+	baseURL := fs.String("base-url", "", "oh-dear API base url, overrides the profile one")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: ohdear [global flags] <sites|teams|config> <command> [flags] [arguments]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Global flags:")
+		fs.PrintDefaults()
+		fmt.Fprintln(stderr)
+		fmt.Fprint(stderr, sitesUsage)
+		fmt.Fprintln(stderr)
+		fmt.Fprint(stderr, teamsUsage)
+		fmt.Fprintln(stderr)
+		fmt.Fprint(stderr, configUsage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	a := &app{stdout: stdout, stderr: stderr, configPath: *configPath, config: cfg}
+	name := cfg.profileName(*profileName)
+
+	switch fs.Arg(0) {
+	case "sites", "teams":
+		if a.profile, err = cfg.profile(name); err != nil {
+			return fail(stderr, err)
+		}
+
+		if *baseURL != "" {
+			a.profile.BaseURL = *baseURL
+		}
+
+		if a.client, err = a.profile.client(); err != nil {
+			return fail(stderr, err)
+		}
+
+		if fs.Arg(0) == "teams" {
+			return fail(stderr, a.teams(fs.Args()[1:]))
+		}
+
+		return fail(stderr, a.sites(fs.Args()[1:]))
+	case "config":
+		return fail(stderr, a.configCmd(name, fs.Args()[1:]))
+	}
+
+	fs.Usage()
+
+	return exitUsage
+}
+
+// fail reports the error and returns the matching exit code.
+func fail(w io.Writer, err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintf(w, "ohdear: %v\n", err)
+
+	return exitCode(err)
+}
+
+// exitCode maps errors to process exit codes.
+func exitCode(err error) int {
+	var (
+		apiErr   *ohdear.Error
+		usageErr *usageError
+	)
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.Is(err, ohdear.ErrEmptyAPIToken):
+		return exitUnauthorized
+	case errors.Is(err, ohdear.ErrInvalidSiteSettings), errors.Is(err, ohdear.ErrInvalidWhitelistURL):
+		return exitInvalid
+	case errors.Is(err, ohdear.ErrTeamNotFound):
+		return exitNotFound
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.Code == http.StatusUnauthorized, apiErr.Code == http.StatusForbidden:
+			return exitUnauthorized
+		case apiErr.Code == http.StatusNotFound:
+			return exitNotFound
+		case apiErr.Code == http.StatusUnprocessableEntity:
+			return exitInvalid
+		case apiErr.Code == http.StatusTooManyRequests:
+			return exitRateLimited
+		case apiErr.Code >= http.StatusInternalServerError:
+			return exitServerError
+		}
+	}
+
+	return exitError
+}
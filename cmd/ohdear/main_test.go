@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func setupServer(t *testing.T) (mux *http.ServeMux, cfg, url string, teardown func()) {
+	mux = http.NewServeMux()
+	srv := httptest.NewServer(mux)
+
+	dir, err := ioutil.TempDir("", "ohdear-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg = filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(cfg, []byte("profiles:\n  default:\n    token: file_token\n    base_url: "+srv.URL+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return mux, cfg, srv.URL, func() {
+		srv.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestRun_SitesList(t *testing.T) {
+	mux, cfg, _, teardown := setupServer(t)
+	defer teardown()
+
+	mux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer file_token", r.Header.Get(ohdear.AuthHeader))
+		assert.Equal(t, "3", r.URL.Query().Get("filter[team_id]"))
+		assert.Equal(t, "10", r.URL.Query().Get("page[size]"))
+		assert.Equal(t, "-url", r.URL.Query().Get("sort"))
+		_, _ = fmt.Fprint(w, testdata.MultipleSitesResponse)
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--config", cfg, "sites", "list", "--team", "3", "--page-size", "10", "--sort", "-url"}, &stdout, &stderr)
+
+	assert.Equal(t, exitOK, code, stderr.String())
+	assert.Equal(t, "ID  URL                        LABEL      TEAM_ID  SUMMARIZED_CHECK_RESULT  LATEST_RUN_DATE\n"+
+		"1   http://yoursite.tld        your-site  1        succeeded                2019-09-16 07:29:02\n"+
+		"2   https://yourothersite.tld  my-site    1        failed                   2019-09-16 07:29:02\n", stdout.String())
+}
+
+func TestRun_OutputFormats(t *testing.T) {
+	mux, cfg, _, teardown := setupServer(t)
+	defer teardown()
+
+	mux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testdata.MultipleSitesResponse)
+	})
+	mux.HandleFunc("/sites/1/downtime", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testdata.DowntimeResponse)
+	})
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			"csv with selected columns",
+			[]string{"sites", "list", "-o", "csv", "--columns", "id,url,summarized_check_result"},
+			"id,url,summarized_check_result\n1,http://yoursite.tld,succeeded\n2,https://yourothersite.tld,failed\n",
+		},
+		{
+			"deprecated column name",
+			[]string{"sites", "list", "-o", "csv", "--columns", "id,summarized_checks_result", "--order-by", "summarized_checks_result"},
+			"id,summarized_check_result\n2,failed\n1,succeeded\n",
+		},
+		{
+			"ndjson ordered descending",
+			[]string{"sites", "list", "--output", "ndjson", "--columns", "id", "--order-by", "-id"},
+			`{"id":2}` + "\n" + `{"id":1}` + "\n",
+		},
+		{
+			"template",
+			[]string{"sites", "list", "--template", "{{.label}}"},
+			"your-site\nmy-site\n",
+		},
+		{
+			"downtime yaml",
+			[]string{"sites", "downtime", "1", "-o", "yaml"},
+			"- started_at: \"2018-09-22 12:00:00\"\n  ended_at: \"2018-09-22 12:05:00\"\n" +
+				"- started_at: \"2018-09-23 08:00:00\"\n  ended_at: null\n",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(append([]string{"--config", cfg}, c.args...), &stdout, &stderr)
+
+			assert.Equal(t, exitOK, code, stderr.String())
+			assert.Equal(t, c.want, stdout.String())
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitUsage, run([]string{"--config", cfg, "sites", "list", "--columns", "nope"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"--config", cfg, "sites", "list", "-o", "xml"}, &stdout, &stderr))
+	assert.Equal(t, exitUsage, run([]string{"--config", cfg, "sites", "list", "--template", "{{.id"}, &stdout, &stderr))
+	assert.Equal(t, exitError, run([]string{"--config", cfg, "sites", "list", "--template", "{{.id.nope}}"}, &stdout, &stderr))
+}
+
+func TestRun_SitesUptimeWithTrailingFlags(t *testing.T) {
+	mux, cfg, _, teardown := setupServer(t)
+	defer teardown()
+
+	mux.HandleFunc("/sites/1/uptime", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "20180922000000", r.URL.Query().Get("filter[started_at]"))
+		assert.Equal(t, "20180924000000", r.URL.Query().Get("filter[ended_at]"))
+		assert.Equal(t, "hour", r.URL.Query().Get("split"))
+		_, _ = fmt.Fprint(w, testdata.UptimeResponse)
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--config", cfg, "sites", "uptime", "1", "--from", "2018-09-22", "--to", "2018-09-24", "--split", "hour"}, &stdout, &stderr)
+
+	assert.Equal(t, exitOK, code, stderr.String())
+	assert.Contains(t, stdout.String(), "2018-09-22 12:00:00  99.98")
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	mux, cfg, _, teardown := setupServer(t)
+	defer teardown()
+
+	mux.HandleFunc("/sites/404", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/sites/401", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/sites/429", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/sites/500", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	cases := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"missing resource", []string{"sites"}, exitUsage},
+		{"unknown command", []string{"sites", "rename"}, exitUsage},
+		{"invalid id", []string{"sites", "get", "one"}, exitUsage},
+		{"missing id", []string{"sites", "delete"}, exitUsage},
+		{"unknown flag", []string{"sites", "list", "--nope"}, exitUsage},
+		{"not found", []string{"sites", "get", "404"}, exitNotFound},
+		{"unauthorized", []string{"sites", "delete", "401"}, exitUnauthorized},
+		{"rate limited", []string{"sites", "get", "429"}, exitRateLimited},
+		{"server error", []string{"sites", "get", "500"}, exitServerError},
+		{"invalid whitelist url", []string{"sites", "broken-links", "whitelist", "add", "1", "nope"}, exitInvalid},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, c.want, run(append([]string{"--config", cfg}, c.args...), &stdout, &stderr))
+		})
+	}
+}
+
+func TestRun_MissingToken(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--config", filepath.Join(os.TempDir(), "missing-ohdear.yaml"), "sites", "list"}, &stdout, &stderr)
+
+	assert.Equal(t, exitUnauthorized, code)
+	assert.Contains(t, stderr.String(), ohdear.ErrEmptyAPIToken.Error())
+}
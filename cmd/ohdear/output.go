@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"strings"
+
+	"github.com/VictorAvelar/goh-dear/ohdear/render"
+)
+
+// siteColumns are the columns rendered by default for sites
+// in table format.
+var siteColumns = []string{"id", "url", "label", "team_id", "summarized_check_result", "latest_run_date"}
+
+// columnAliases maps the column names accepted by previous releases
+// to their current name.
+var columnAliases = map[string]string{
+	"summarized_checks_result": "summarized_check_result",
+}
+
+// output holds the rendering flags shared by the commands.
+type output struct {
+	format   render.Format
+	columns  string
+	orderBy  string
+	template string
+	defaults []string
+}
+
+// outputFlags registers the rendering flags, the default columns are
+// used by the table format when no columns are selected.
+//
+// The default format is the one configured by the current profile.
+func (a *app) outputFlags(fs *flag.FlagSet, defaults ...string) *output {
+	o := &output{format: a.defaultFormat(), defaults: defaults}
+	fs.Var((*formatValue)(&o.format), "o", "output format: table, json, ndjson, yaml, csv or template")
+	fs.Var((*formatValue)(&o.format), "output", "output format: table, json, ndjson, yaml, csv or template")
+	fs.StringVar(&o.columns, "columns", "", "comma separated list of columns to render")
+	fs.StringVar(&o.orderBy, "order-by", "", "column used to order the output, prefix with - for descending order")
+	fs.StringVar(&o.template, "template", "", "text/template executed for every item, implies --output template")
+
+	return o
+}
+
+func (o *output) options() (opts render.Options) {
+	opts.Format = o.format
+	if o.template != "" {
+		opts.Format = render.Template
+	}
+
+	for _, c := range splitList(o.columns) {
+		opts.Columns = append(opts.Columns, columnName(c))
+	}
+	if len(opts.Columns) == 0 && opts.Format == render.Table {
+		opts.Columns = o.defaults
+	}
+
+	opts.SortBy = strings.TrimSpace(o.orderBy)
+	if desc := strings.TrimPrefix(opts.SortBy, "-"); desc != opts.SortBy {
+		opts.SortBy = "-" + columnName(desc)
+	} else {
+		opts.SortBy = columnName(opts.SortBy)
+	}
+	opts.Template = o.template
+
+	return opts
+}
+
+// columnName resolves the aliases of a column.
+func columnName(c string) string {
+	if name, ok := columnAliases[c]; ok {
+		return name
+	}
+
+	return c
+}
+
+// formatValue is a flag.Value validating the output format.
+type formatValue render.Format
+
+func (f *formatValue) String() string {
+	return string(*f)
+}
+
+func (f *formatValue) Set(s string) error {
+	v, err := render.ParseFormat(s)
+	if err != nil {
+		return err
+	}
+
+	*f = formatValue(v)
+
+	return nil
+}
+
+// defaultFormat returns the output format configured by the profile.
+func (a *app) defaultFormat() render.Format {
+	if a.profile != nil && a.profile.Output != "" {
+		f, _ := render.ParseFormat(a.profile.Output)
+		return f
+	}
+
+	return render.Table
+}
+
+// render writes the items to the standard output, invalid rendering
+// options are reported as usage errors.
+func (a *app) render(o *output, items interface{}) error {
+	err := render.Render(a.stdout, items, o.options())
+	switch {
+	case errors.Is(err, render.ErrUnknownFormat),
+		errors.Is(err, render.ErrUnknownColumn),
+		errors.Is(err, render.ErrEmptyTemplate),
+		errors.Is(err, render.ErrInvalidTemplate):
+		return usagef("%v", err)
+	}
+
+	return err
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+const sitesUsage = `Sites commands:
+  sites list [--team id] [--type http|ping|tcp] [--page-size n] [--page n] [--sort field]
+  sites get <id>
+  sites get-by-url <url>
+  sites create --url url [--team id] [--label label] [--checks uptime,broken_links]
+  sites create --type ping --host host [--team id] [--label label]
+  sites create --type tcp --host host --port port [--tcp-expect open|closed]
+  sites delete <id>
+  sites uptime <id> [--from date] [--to date] [--split hour|day|month]
+  sites downtime <id> [--from date] [--to date]
+  sites broken-links whitelist add <id> <url>...
+
+Commands printing resources accept -o/--output table, json, ndjson, yaml,
+csv or template, --columns id,url to select columns, --order-by column
+to sort the output and --template '{{.url}}' for custom formats.
+
+Dates accept the 2006-01-02, RFC3339 or 20060102150405 layouts,
+the default window covers the last 7 days.
+`
+
+// defaultWindow is the period covered by uptime and downtime
+// requests when no dates are provided.
+const defaultWindow = 7 * 24 * time.Hour
+
+func (a *app) sites(args []string) error {
+	if len(args) == 0 {
+		return usagef("missing sites command")
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "list":
+		return a.sitesList(args)
+	case "get":
+		return a.sitesGet(args)
+	case "get-by-url":
+		return a.sitesGetByURL(args)
+	case "create":
+		return a.sitesCreate(args)
+	case "delete":
+		return a.sitesDelete(args)
+	case "uptime":
+		return a.sitesUptime(args)
+	case "downtime":
+		return a.sitesDowntime(args)
+	case "broken-links":
+		return a.sitesBrokenLinks(args)
+	}
+
+	return usagef("unknown sites command %q", cmd)
+}
+
+func (a *app) sitesList(args []string) error {
+	var filters ohdear.ListSitesRequestFilters
+	fs := a.flagSet("sites list")
+	fs.UintVar(&filters.FilterByTeamID, "team", a.profile.TeamID, "only list the sites of the team")
+	fs.StringVar((*string)(&filters.FilterByType), "type", "", "only list the monitors of the type: http, ping or tcp")
+	fs.UintVar(&filters.PageSize, "page-size", 0, "amount of sites per page")
+	fs.UintVar(&filters.PageNumber, "page", 0, "page number")
+	fs.StringVar(&filters.SortBy, "sort", "", "sort field, prefix with - for descending order")
+	out := a.outputFlags(fs, siteColumns...)
+
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	sites, err := a.client.Sites.List(filters)
+	if err != nil {
+		return err
+	}
+
+	return a.render(out, sites)
+}
+
+func (a *app) sitesGet(args []string) error {
+	fs := a.flagSet("sites get <id>")
+	out := a.outputFlags(fs, siteColumns...)
+
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+
+	site, err := a.client.Sites.Get(id)
+	if err != nil {
+		return err
+	}
+
+	return a.render(out, site)
+}
+
+func (a *app) sitesGetByURL(args []string) error {
+	fs := a.flagSet("sites get-by-url <url>")
+	out := a.outputFlags(fs, siteColumns...)
+
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	site, err := a.client.Sites.GetByURL(pos[0])
+	if err != nil {
+		return err
+	}
+
+	return a.render(out, site)
+}
+
+func (a *app) sitesCreate(args []string) error {
+	var (
+		settings ohdear.SiteSettings
+		checks   string
+	)
+
+	fs := a.flagSet("sites create")
+	fs.StringVar((*string)(&settings.Type), "type", "", "monitor type: http, ping or tcp, defaults to http")
+	fs.StringVar(&settings.URL, "url", "", "url of the site, required by http monitors")
+	fs.StringVar(&settings.Host, "host", "", "host of ping and tcp monitors")
+	fs.UintVar(&settings.Port, "port", 0, "port of tcp monitors")
+	fs.StringVar((*string)(&settings.TCPExpectation), "tcp-expect", "", "expected state of the tcp port: open or closed")
+	fs.UintVar(&settings.TeamID, "team", a.profile.TeamID, "team owning the site")
+	fs.StringVar(&settings.Label, "label", "", "label of the site")
+	fs.StringVar(&checks, "checks", "", "comma separated list of checks to enable")
+	out := a.outputFlags(fs, siteColumns...)
+
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	switch settings.Type {
+	case ohdear.MonitorPing:
+		if settings.Host == "" {
+			return usagef("--host is required by ping monitors")
+		}
+	case ohdear.MonitorTCP:
+		if settings.Host == "" || settings.Port == 0 {
+			return usagef("--host and --port are required by tcp monitors")
+		}
+	default:
+		if settings.URL == "" {
+			return usagef("--url is required")
+		}
+	}
+
+	for _, c := range splitList(checks) {
+		settings.Checks = append(settings.Checks, ohdear.CheckType(c))
+	}
+
+	site, err := a.client.Sites.CreateWithSettings(context.Background(), settings)
+	if err != nil {
+		return err
+	}
+
+	return a.render(out, site)
+}
+
+func (a *app) sitesDelete(args []string) error {
+	pos, err := parseArgs(a.flagSet("sites delete <id>"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+
+	if err := a.client.Sites.Delete(id); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "site %d deleted\n", id)
+
+	return nil
+}
+
+func (a *app) sitesUptime(args []string) error {
+	var split string
+	fs := a.flagSet("sites uptime <id>")
+	from, to := windowFlags(fs)
+	fs.StringVar(&split, "split", string(ohdear.SplitByDay), "aggregation: hour, day or month")
+	out := a.outputFlags(fs)
+
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+
+	start, end, err := parseWindow(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	switch ohdear.SplitValue(split) {
+	case ohdear.SplitByHour, ohdear.SplitByDay, ohdear.SplitByMonth:
+	default:
+		return usagef("invalid split %q", split)
+	}
+
+	ur, err := a.client.Sites.GetUptimePercentage(id, ohdear.UptimeRequestFilters{
+		StartedAt: start,
+		EndedAt:   end,
+		Split:     ohdear.SplitValue(split),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.render(out, ur.Data)
+}
+
+func (a *app) sitesDowntime(args []string) error {
+	fs := a.flagSet("sites downtime <id>")
+	from, to := windowFlags(fs)
+	out := a.outputFlags(fs)
+
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+
+	start, end, err := parseWindow(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	dr, err := a.client.Sites.GetDowntimePeriods(id, ohdear.DowntimeRequestFilters{
+		StartedAt: start,
+		EndedAt:   end,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.render(out, dr.Data)
+}
+
+func (a *app) sitesBrokenLinks(args []string) error {
+	if len(args) < 2 || args[0] != "whitelist" || args[1] != "add" {
+		return usagef("usage: sites broken-links whitelist add <id> <url>...")
+	}
+
+	pos, err := parseArgs(a.flagSet("sites broken-links whitelist add <id> <url>..."), args[2:], 2)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(pos[0])
+	if err != nil {
+		return err
+	}
+
+	site, err := a.client.Sites.AddManyToBrokenLinkWhitelist(id, pos[1:]...)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range site.BrokenLinksWhitelistedURLS {
+		fmt.Fprintln(a.stdout, u)
+	}
+
+	return nil
+}
+
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+
+	return fs
+}
+
+// parseArgs parses flags placed before or after the positional
+// arguments and checks that at least min positional arguments are given.
+func parseArgs(fs *flag.FlagSet, args []string, min int) (pos []string, err error) {
+	for {
+		if err = fs.Parse(args); err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+
+		pos = append(pos, args[0])
+		args = args[1:]
+	}
+
+	if len(pos) < min {
+		return nil, usagef("usage: %s", fs.Name())
+	}
+
+	return pos, nil
+}
+
+func parseID(s string) (uint, error) {
+	id, err := strconv.ParseUint(s, 10, 0)
+	if err != nil {
+		return 0, usagef("invalid id %q", s)
+	}
+
+	return uint(id), nil
+}
+
+func windowFlags(fs *flag.FlagSet) (from, to *string) {
+	from = fs.String("from", "", "start of the window")
+	to = fs.String("to", "", "end of the window, defaults to now")
+
+	return
+}
+
+// parseWindow converts the user provided dates into the
+// layout expected by the API filters.
+func parseWindow(from, to string) (start, end string, err error) {
+	e := time.Now()
+	if to != "" {
+		if e, err = parseDate(to); err != nil {
+			return
+		}
+	}
+
+	s := e.Add(-defaultWindow)
+	if from != "" {
+		if s, err = parseDate(from); err != nil {
+			return
+		}
+	}
+
+	return ohdear.FilterDate(s), ohdear.FilterDate(e), nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02", ohdear.FilterDateLayout} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, usagef("invalid date %q", s)
+}
+
+func splitList(s string) (out []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+
+	return
+}
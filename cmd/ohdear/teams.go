@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+)
+
+const teamsUsage = `Teams commands:
+  teams list
+  teams resolve <name>
+`
+
+// teamColumns are the columns rendered by default for teams
+// in table format.
+var teamColumns = []string{"id", "name", "role"}
+
+func (a *app) teams(args []string) error {
+	if len(args) == 0 {
+		return usagef("missing teams command")
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "list":
+		return a.teamsList(args)
+	case "resolve":
+		return a.teamsResolve(args)
+	}
+
+	return usagef("unknown teams command %q", cmd)
+}
+
+func (a *app) teamsList(args []string) error {
+	fs := a.flagSet("teams list")
+	out := a.outputFlags(fs, teamColumns...)
+
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	teams, err := a.client.Teams.List()
+	if err != nil {
+		return err
+	}
+
+	return a.render(out, teams)
+}
+
+// teamsResolve prints the id of the team with the given name,
+// so it can be used by scripts and the --team flags.
+func (a *app) teamsResolve(args []string) error {
+	pos, err := parseArgs(a.flagSet("teams resolve <name>"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := a.client.Teams.ResolveID(pos[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(a.stdout, id)
+
+	return err
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestRun_TeamsList(t *testing.T) {
+	mux, cfg, _, teardown := setupServer(t)
+	defer teardown()
+
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testdata.MeResponse)
+	})
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "teams", "list"}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "ID  NAME        ROLE\n"+
+		"1   Acme        owner\n"+
+		"9   Globex Ops  member\n", stdout.String())
+}
+
+func TestRun_TeamsResolve(t *testing.T) {
+	mux, cfg, _, teardown := setupServer(t)
+	defer teardown()
+
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testdata.MeResponse)
+	})
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"--config", cfg, "teams", "resolve", "globex ops"}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "9\n", stdout.String())
+
+	stderr.Reset()
+	assert.Equal(t, exitNotFound, run([]string{"--config", cfg, "teams", "resolve", "Initech"}, &stdout, &stderr))
+	assert.Equal(t, "ohdear: no single team matches the given name: \"Initech\"\n", stderr.String())
+
+	assert.Equal(t, exitUsage, run([]string{"--config", cfg, "teams"}, &stdout, &stderr))
+}
@@ -5,4 +5,5 @@ go 1.14
 require (
 	github.com/google/go-querystring v1.0.0
 	github.com/stretchr/testify v1.6.1
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,189 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+// ApplicationHealthChecksPath is the resource path appended to a site.
+const ApplicationHealthChecksPath string = "application-health-checks"
+
+// ApplicationHealthChecksSrv operates over the application health checks
+// reported for a site.
+type ApplicationHealthChecksSrv srv
+
+// ApplicationHealthStatus describes the state reported by an
+// application health check.
+type ApplicationHealthStatus string
+
+// Supported ApplicationHealthStatus values.
+const (
+	ApplicationHealthOK      ApplicationHealthStatus = "ok"
+	ApplicationHealthWarning ApplicationHealthStatus = "warning"
+	ApplicationHealthFailed  ApplicationHealthStatus = "failed"
+	ApplicationHealthCrashed ApplicationHealthStatus = "crashed"
+	ApplicationHealthSkipped ApplicationHealthStatus = "skipped"
+)
+
+// ApplicationHealthCheck represents the latest result of an
+// application health check.
+type ApplicationHealthCheck struct {
+	ID           uint                    `json:"id,omitempty"`
+	Name         string                  `json:"name,omitempty"`
+	Label        string                  `json:"label,omitempty"`
+	Status       ApplicationHealthStatus `json:"status,omitempty"`
+	ShortSummary string                  `json:"short_summary,omitempty"`
+	Message      string                  `json:"message,omitempty"`
+	Meta         map[string]interface{}  `json:"meta,omitempty"`
+	DetectedAt   *CustomDate             `json:"detected_at,omitempty"`
+	UpdatedAt    *CustomDate             `json:"updated_at,omitempty"`
+	SnoozedUntil *CustomDate             `json:"snoozed_until,omitempty"`
+}
+
+// ApplicationHealthCheckResult represents a single entry in the
+// history of an application health check.
+type ApplicationHealthCheckResult struct {
+	ID           uint                    `json:"id,omitempty"`
+	Status       ApplicationHealthStatus `json:"status,omitempty"`
+	ShortSummary string                  `json:"short_summary,omitempty"`
+	Message      string                  `json:"message,omitempty"`
+	Meta         map[string]interface{}  `json:"meta,omitempty"`
+	DetectedAt   *CustomDate             `json:"detected_at,omitempty"`
+}
+
+// ApplicationHealthChecksResponse is a page of application health
+// checks inside an outer data wrapper.
+type ApplicationHealthChecksResponse struct {
+	Data  []*ApplicationHealthCheck `json:"data"`
+	Links *PaginationLinks          `json:"links,omitempty"`
+	Meta  *PaginationMeta           `json:"meta,omitempty"`
+}
+
+// ApplicationHealthCheckResultsResponse is a page of application health
+// check results inside an outer data wrapper.
+type ApplicationHealthCheckResultsResponse struct {
+	Data  []*ApplicationHealthCheckResult `json:"data"`
+	Links *PaginationLinks                `json:"links,omitempty"`
+	Meta  *PaginationMeta                 `json:"meta,omitempty"`
+}
+
+// ApplicationHealthRequestFilters adds the pagination parameters
+// to application health requests.
+//
+// None of the values are required.
+type ApplicationHealthRequestFilters struct {
+	PageSize   uint `url:"page[size],omitempty"`
+	PageNumber uint `url:"page[number],omitempty"`
+}
+
+// SnoozeRequest describes the request body required to
+// snooze an application health check.
+type SnoozeRequest struct {
+	Minutes uint `json:"minutes"`
+}
+
+// List returns the latest application health check results of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#application-health-checks
+func (ahs *ApplicationHealthChecksSrv) List(siteID uint, filters ApplicationHealthRequestFilters) (ar *ApplicationHealthChecksResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := ahs.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s?%s", SitesBasePath, siteID, ApplicationHealthChecksPath, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ahs.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &ar); err != nil {
+		return
+	}
+
+	return
+}
+
+// History returns the results history of a single application health check.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#application-health-checks
+func (ahs *ApplicationHealthChecksSrv) History(siteID, checkID uint, filters ApplicationHealthRequestFilters) (ar *ApplicationHealthCheckResultsResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := ahs.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s/%d?%s", SitesBasePath, siteID, ApplicationHealthChecksPath, checkID, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ahs.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &ar); err != nil {
+		return
+	}
+
+	return
+}
+
+// Snooze silences the notifications of an application health check
+// for the given amount of minutes.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#application-health-checks
+func (ahs *ApplicationHealthChecksSrv) Snooze(siteID, checkID, minutes uint) (check *ApplicationHealthCheck, err error) {
+	req, err := ahs.client.NewAPIRequest(
+		http.MethodPost,
+		fmt.Sprintf("%s/%d/%s/%d/snooze", SitesBasePath, siteID, ApplicationHealthChecksPath, checkID),
+		SnoozeRequest{Minutes: minutes},
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ahs.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &check); err != nil {
+		return
+	}
+
+	return
+}
+
+// Unsnooze resumes the notifications of an application health check.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#application-health-checks
+func (ahs *ApplicationHealthChecksSrv) Unsnooze(siteID, checkID uint) (check *ApplicationHealthCheck, err error) {
+	req, err := ahs.client.NewAPIRequest(
+		http.MethodPost,
+		fmt.Sprintf("%s/%d/%s/%d/unsnooze", SitesBasePath, siteID, ApplicationHealthChecksPath, checkID),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ahs.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &check); err != nil {
+		return
+	}
+
+	return
+}
@@ -0,0 +1,133 @@
+package ohdear
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestApplicationHealthChecksSrv_List(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/application-health-checks", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "2", r.URL.Query().Get("page[number]"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.ApplicationHealthChecksResponse)
+	})
+
+	got, err := tClient.ApplicationHealthChecks.List(1, ApplicationHealthRequestFilters{PageNumber: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 2)
+	assert.Equal(t, "UsedDiskSpace", got.Data[0].Name)
+	assert.Equal(t, ApplicationHealthWarning, got.Data[0].Status)
+	assert.Equal(t, float64(76), got.Data[0].Meta["disk_space_used_percentage"])
+	assert.Equal(t, 2021, got.Data[0].DetectedAt.Year())
+	assert.False(t, got.Meta.HasNextPage())
+}
+
+func TestApplicationHealthChecksSrv_History(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/application-health-checks/1", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.ApplicationHealthCheckResultsResponse)
+	})
+
+	got, err := tClient.ApplicationHealthChecks.History(1, 1, ApplicationHealthRequestFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 2)
+	assert.Equal(t, ApplicationHealthOK, got.Data[1].Status)
+	assert.True(t, got.Meta.HasNextPage())
+}
+
+func TestApplicationHealthChecksSrv_Snooze(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	cases := []struct {
+		name    string
+		path    string
+		body    string
+		status  int
+		wantErr bool
+		call    func() (*ApplicationHealthCheck, error)
+	}{
+		{
+			"snooze an application health check",
+			"/sites/1/application-health-checks/1/snooze",
+			`{"minutes":60}` + "\n",
+			http.StatusOK,
+			false,
+			func() (*ApplicationHealthCheck, error) {
+				return tClient.ApplicationHealthChecks.Snooze(1, 1, 60)
+			},
+		},
+		{
+			"unsnooze a missing application health check",
+			"/sites/1/application-health-checks/2/unsnooze",
+			"",
+			http.StatusNotFound,
+			true,
+			func() (*ApplicationHealthCheck, error) {
+				return tClient.ApplicationHealthChecks.Unsnooze(1, 2)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			tMux.HandleFunc(c.path, func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodPost)
+				testBody(t, r, c.body)
+
+				w.WriteHeader(c.status)
+				if c.status == http.StatusOK {
+					_, _ = fmt.Fprint(w, testdata.SnoozedApplicationHealthCheckResponse)
+				}
+			})
+
+			got, err := c.call()
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.NotNil(t, got.SnoozedUntil)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/google/go-querystring/query"
+)
+
+// BrokenLinksBasePath is the resource path prefix.
+const BrokenLinksBasePath string = "broken-links"
+
+// BrokenLinksSrv operates over the broken links detected on a site.
+type BrokenLinksSrv srv
+
+// BrokenLink describes a link which failed to resolve
+// when crawling a site.
+type BrokenLink struct {
+	CrawledURL string `json:"crawled_url,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	FoundOnURL string `json:"found_on_url,omitempty"`
+	LinkText   string `json:"link_text,omitempty"`
+}
+
+// BrokenLinksResponse is a page of broken links
+// inside an outer data wrapper.
+type BrokenLinksResponse struct {
+	Data  []*BrokenLink    `json:"data"`
+	Links *PaginationLinks `json:"links,omitempty"`
+	Meta  *PaginationMeta  `json:"meta,omitempty"`
+}
+
+// BrokenLinksRequestFilters adds the query string parameters
+// to paginate and filter the broken links of a site.
+//
+// None of the values are required.
+type BrokenLinksRequestFilters struct {
+	PageSize   uint `url:"page[size],omitempty"`
+	PageNumber uint `url:"page[number],omitempty"`
+	StatusCode int  `url:"filter[status_code],omitempty"`
+}
+
+// BrokenLinksPage groups the broken links found on a single page.
+type BrokenLinksPage struct {
+	FoundOnURL string
+	Links      []*BrokenLink
+}
+
+// List returns the broken links detected for a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#broken-links
+func (bls *BrokenLinksSrv) List(siteID uint, filters BrokenLinksRequestFilters) (br *BrokenLinksResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := bls.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d?%s", BrokenLinksBasePath, siteID, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := bls.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &br); err != nil {
+		return
+	}
+
+	return
+}
+
+// GroupBrokenLinksByPage groups broken links by the page
+// they were found on.
+//
+// Pages are sorted by their url and links keep their original order.
+func GroupBrokenLinksByPage(links []*BrokenLink) (pages []*BrokenLinksPage) {
+	idx := make(map[string]*BrokenLinksPage)
+	for _, l := range links {
+		p, ok := idx[l.FoundOnURL]
+		if !ok {
+			p = &BrokenLinksPage{FoundOnURL: l.FoundOnURL}
+			idx[l.FoundOnURL] = p
+			pages = append(pages, p)
+		}
+		p.Links = append(p.Links, l)
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		return pages[i].FoundOnURL < pages[j].FoundOnURL
+	})
+
+	return
+}
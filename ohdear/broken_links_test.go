@@ -0,0 +1,56 @@
+package ohdear
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestBrokenLinksSrv_List(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/broken-links/1", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "404", r.URL.Query().Get("filter[status_code]"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.BrokenLinksResponse)
+	})
+
+	got, err := tClient.BrokenLinks.List(1, BrokenLinksRequestFilters{StatusCode: http.StatusNotFound})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 3)
+	assert.Equal(t, "https://yoursite.tld/missing", got.Data[0].CrawledURL)
+	assert.Equal(t, http.StatusNotFound, got.Data[0].StatusCode)
+	assert.Equal(t, "Read more", got.Data[0].LinkText)
+}
+
+func TestGroupBrokenLinksByPage(t *testing.T) {
+	links := []*BrokenLink{
+		{CrawledURL: "https://yoursite.tld/missing", FoundOnURL: "https://yoursite.tld/blog"},
+		{CrawledURL: "https://external.tld/gone", FoundOnURL: "https://yoursite.tld/about"},
+		{CrawledURL: "https://yoursite.tld/old-post", FoundOnURL: "https://yoursite.tld/blog"},
+	}
+
+	got := GroupBrokenLinksByPage(links)
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "https://yoursite.tld/about", got[0].FoundOnURL)
+	assert.Len(t, got[0].Links, 1)
+	assert.Equal(t, "https://yoursite.tld/blog", got[1].FoundOnURL)
+	assert.Equal(t, []*BrokenLink{links[0], links[2]}, got[1].Links)
+	assert.Nil(t, GroupBrokenLinksByPage(nil))
+}
@@ -0,0 +1,139 @@
+package ohdear
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// CertificateHealthBasePath is the resource path prefix.
+const CertificateHealthBasePath string = "certificate-health"
+
+// CertificateHealthSrv operates over the certificate health of a site.
+type CertificateHealthSrv srv
+
+// CertificateHealth describes the certificate served by a site
+// and the results of the checks performed against it.
+type CertificateHealth struct {
+	Details      *CertificateDetails `json:"certificate_details,omitempty"`
+	Checks       []*CertificateCheck `json:"certificate_checks,omitempty"`
+	ChainIssuers []string            `json:"certificate_chain_issuers,omitempty"`
+}
+
+// CertificateDetails contains the properties of a certificate.
+type CertificateDetails struct {
+	Issuer            string      `json:"issuer,omitempty"`
+	Domain            string      `json:"domain,omitempty"`
+	AdditionalDomains []string    `json:"additional_domains,omitempty"`
+	Fingerprint       string      `json:"fingerprint,omitempty"`
+	ValidFrom         *CustomDate `json:"valid_from,omitempty"`
+	ValidUntil        *CustomDate `json:"valid_until,omitempty"`
+}
+
+// CertificateCheck is the result of a single certificate verification.
+type CertificateCheck struct {
+	Type   string `json:"type,omitempty"`
+	Label  string `json:"label,omitempty"`
+	Passed bool   `json:"passed"`
+}
+
+// ExpiringCertificate relates a site with its soon to expire certificate.
+type ExpiringCertificate struct {
+	Site   *Site
+	Health *CertificateHealth
+}
+
+// DaysUntilExpiry returns the amount of whole days left before the
+// certificate expires, negative values mean the certificate expired.
+//
+// It returns 0 when the expiry date is unknown.
+func (ch *CertificateHealth) DaysUntilExpiry() int {
+	if !ch.hasExpiry() {
+		return 0
+	}
+
+	return daysUntil(ch.Details.ValidUntil.Time)
+}
+
+func (ch *CertificateHealth) hasExpiry() bool {
+	return ch != nil && ch.Details != nil && ch.Details.ValidUntil != nil
+}
+
+// FailedChecks returns the certificate checks which did not pass.
+func (ch *CertificateHealth) FailedChecks() (failed []*CertificateCheck) {
+	for _, c := range ch.Checks {
+		if !c.Passed {
+			failed = append(failed, c)
+		}
+	}
+
+	return
+}
+
+// Get retrieves the certificate health of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#certificate-health
+func (chs *CertificateHealthSrv) Get(siteID uint) (*CertificateHealth, error) {
+	return chs.get(context.Background(), siteID)
+}
+
+func (chs *CertificateHealthSrv) get(ctx context.Context, siteID uint) (ch *CertificateHealth, err error) {
+	req, err := chs.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d", CertificateHealthBasePath, siteID),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := chs.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &ch); err != nil {
+		return
+	}
+
+	return
+}
+
+// ExpiringWithin walks all the https sites returned by SitesSrv.ListAll
+// and returns the ones whose certificate expires before the given
+// duration elapses, sorted by expiry date.
+func (chs *CertificateHealthSrv) ExpiringWithin(ctx context.Context, d time.Duration) (expiring []*ExpiringCertificate, err error) {
+	sites, err := chs.client.Sites.ListAll(ctx, ListSitesRequestFilters{})
+	if err != nil {
+		return
+	}
+
+	deadline := time.Now().Add(d)
+	for _, s := range sites {
+		if !s.UsesHTTPS {
+			continue
+		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ch, err := chs.get(ctx, s.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if ch.hasExpiry() && ch.Details.ValidUntil.Before(deadline) {
+			expiring = append(expiring, &ExpiringCertificate{Site: s, Health: ch})
+		}
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].Health.Details.ValidUntil.Before(expiring[j].Health.Details.ValidUntil.Time)
+	})
+
+	return
+}
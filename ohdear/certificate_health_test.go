@@ -0,0 +1,107 @@
+package ohdear
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestCertificateHealthSrv_Get(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/certificate-health/2", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.CertificateHealthResponse)
+	})
+
+	got, err := tClient.CertificateHealth.Get(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Let's Encrypt Authority X3", got.Details.Issuer)
+	assert.Equal(t, []string{"www.yourothersite.tld", "yourothersite.tld"}, got.Details.AdditionalDomains)
+	assert.Equal(t, time.November, got.Details.ValidUntil.Month())
+	assert.Equal(t, []string{"Let's Encrypt Authority X3", "DST Root CA X3"}, got.ChainIssuers)
+	assert.Len(t, got.FailedChecks(), 1)
+	assert.True(t, got.DaysUntilExpiry() < 0)
+}
+
+func TestCertificateHealth_DaysUntilExpiry(t *testing.T) {
+	in := func(d time.Duration) *CertificateHealth {
+		return &CertificateHealth{
+			Details: &CertificateDetails{ValidUntil: &CustomDate{time.Now().Add(d)}},
+		}
+	}
+
+	assert.Equal(t, 10, in(10*24*time.Hour+time.Hour).DaysUntilExpiry())
+	assert.Equal(t, 0, in(time.Hour).DaysUntilExpiry())
+	assert.Equal(t, -1, in(-time.Hour).DaysUntilExpiry(), "just expired certificates are not reported as unknown")
+	assert.Equal(t, -3, in(-3*24*time.Hour+time.Hour).DaysUntilExpiry())
+	assert.Equal(t, 0, (&CertificateHealth{}).DaysUntilExpiry())
+}
+
+func TestCertificateHealthSrv_ExpiringWithin(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	soon := time.Now().UTC().Add(5 * 24 * time.Hour).Format("2006-01-02 15:04:05")
+	later := time.Now().UTC().Add(10 * 24 * time.Hour).Format("2006-01-02 15:04:05")
+
+	tMux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[number]") == "2" {
+			_, _ = fmt.Fprint(w, testdata.SitesLastPageResponse)
+			return
+		}
+		_, _ = fmt.Fprint(w, testdata.SitesFirstPageResponse)
+	})
+	tMux.HandleFunc("/certificate-health/1", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("sites without https should be skipped")
+	})
+	tMux.HandleFunc("/certificate-health/2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, strings.Replace(testdata.CertificateHealthResponse, "2019-11-26 14:48:11", soon, 1))
+	})
+	tMux.HandleFunc("/certificate-health/3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, strings.Replace(testdata.CertificateHealthResponse, "2019-11-26 14:48:11", later, 1))
+	})
+
+	got, err := tClient.CertificateHealth.ExpiringWithin(context.Background(), 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, uint(2), got[0].Site.ID)
+	assert.Equal(t, uint(3), got[1].Site.ID)
+
+	got, err = tClient.CertificateHealth.ExpiringWithin(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, got)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tClient.CertificateHealth.ExpiringWithin(ctx, 24*time.Hour)
+	assert.Equal(t, context.Canceled, err)
+}
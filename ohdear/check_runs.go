@@ -0,0 +1,202 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+// Check runs resource paths.
+const (
+	ChecksBasePath string = "checks"
+	CheckRunsPath  string = "runs"
+)
+
+// CheckRunsSrv operates over the historical runs of a check.
+type CheckRunsSrv srv
+
+// CheckRun is a single execution of a check, NotificationMessage
+// is the text sent to the notification destinations.
+type CheckRun struct {
+	ID                  uint        `json:"id,omitempty"`
+	CheckID             uint        `json:"check_id,omitempty"`
+	Result              CheckResult `json:"result,omitempty"`
+	Summary             string      `json:"summary,omitempty"`
+	NotificationMessage string      `json:"notification_message,omitempty"`
+	StartedAt           *CustomDate `json:"started_at,omitempty"`
+	EndedAt             *CustomDate `json:"ended_at,omitempty"`
+}
+
+// CheckRunsResponse is a page of check runs inside an outer data wrapper.
+type CheckRunsResponse struct {
+	Data  []*CheckRun      `json:"data"`
+	Links *PaginationLinks `json:"links,omitempty"`
+	Meta  *PaginationMeta  `json:"meta,omitempty"`
+}
+
+// CheckRunsRequestFilters adds the query string parameters
+// to paginate and filter check runs by start date.
+// The specified dates should be represented as follows:
+// 20200801000000.
+//
+// None of the values are required.
+type CheckRunsRequestFilters struct {
+	PageSize   uint   `url:"page[size],omitempty"`
+	PageNumber uint   `url:"page[number],omitempty"`
+	StartedAt  string `url:"filter[started_at],omitempty"`
+	EndedAt    string `url:"filter[ended_at],omitempty"`
+}
+
+// List returns a page of the runs of a check.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#check-runs
+func (crs *CheckRunsSrv) List(checkID uint, filters CheckRunsRequestFilters) (cr *CheckRunsResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := crs.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s?%s", ChecksBasePath, checkID, CheckRunsPath, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := crs.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &cr); err != nil {
+		return
+	}
+
+	return
+}
+
+// Window walks every page of runs of a check and returns the ones
+// overlapping the [start, end) window, including the runs started
+// inside the window which ended after end or are still running.
+//
+// Only the started_at filter is sent, the runs started at or after
+// end are discarded once received.
+func (crs *CheckRunsSrv) Window(checkID uint, start, end time.Time) (runs []*CheckRun, err error) {
+	filters := CheckRunsRequestFilters{
+		PageNumber: 1,
+		StartedAt:  FilterDate(start),
+	}
+
+	for {
+		cr, err := crs.List(checkID, filters)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range cr.Data {
+			if r.overlaps(start, end) {
+				runs = append(runs, r)
+			}
+		}
+
+		if !cr.Meta.HasNextPage() {
+			return runs, nil
+		}
+
+		filters.PageNumber++
+	}
+}
+
+// overlaps reports whether the run intersects the [start, end) window,
+// runs without end date are still running.
+func (r *CheckRun) overlaps(start, end time.Time) bool {
+	if r == nil || r.StartedAt == nil || !r.StartedAt.Before(end) {
+		return false
+	}
+
+	return r.EndedAt == nil || !r.EndedAt.Before(start)
+}
+
+// StateInterval is a period during which every run of a check
+// had the same result. Summary is the summary of its first run.
+type StateInterval struct {
+	Result  CheckResult `json:"result"`
+	Start   time.Time   `json:"start"`
+	End     time.Time   `json:"end"`
+	Runs    int         `json:"runs"`
+	Summary string      `json:"summary,omitempty"`
+}
+
+// Duration returns the length of the interval.
+func (si *StateInterval) Duration() time.Duration {
+	return si.End.Sub(si.Start)
+}
+
+// Failing reports whether the interval describes a failed or errored check.
+func (si *StateInterval) Failing() bool {
+	return si.Result == CheckFailed || si.Result == CheckErrored
+}
+
+// BuildTimeline converts the runs of a check into consecutive state
+// intervals, a new interval starts every time the result changes.
+//
+// Runs are sorted by start date, the ones without start date or
+// still pending are ignored. Every interval ends when the next one
+// starts and the last one ends at until, or at the end of its last
+// run when until is zero.
+func BuildTimeline(runs []*CheckRun, until time.Time) (timeline []*StateInterval) {
+	sorted := make([]*CheckRun, 0, len(runs))
+	for _, r := range runs {
+		if r == nil || r.StartedAt == nil || r.Result == "" || r.Result == CheckPending {
+			continue
+		}
+
+		sorted = append(sorted, r)
+	}
+
+	if len(sorted) == 0 {
+		return
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.Before(sorted[j].StartedAt.Time)
+	})
+
+	var current *StateInterval
+	for _, r := range sorted {
+		if current != nil && current.Result == r.Result {
+			current.Runs++
+			continue
+		}
+
+		if current != nil {
+			current.End = r.StartedAt.Time
+		}
+
+		current = &StateInterval{
+			Result:  r.Result,
+			Start:   r.StartedAt.Time,
+			Runs:    1,
+			Summary: r.Summary,
+		}
+		timeline = append(timeline, current)
+	}
+
+	last := sorted[len(sorted)-1]
+	switch {
+	case !until.IsZero():
+		current.End = until
+	case last.EndedAt != nil:
+		current.End = last.EndedAt.Time
+	default:
+		current.End = last.StartedAt.Time
+	}
+
+	if current.End.Before(current.Start) {
+		current.End = current.Start
+	}
+
+	return
+}
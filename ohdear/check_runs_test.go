@@ -0,0 +1,96 @@
+package ohdear
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestCheckRunsSrv_Window(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/checks/100/runs", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "20200801000000", r.URL.Query().Get("filter[started_at]"))
+		_, ended := r.URL.Query()["filter[ended_at]"]
+		assert.False(t, ended, "runs ending after the window must be listed")
+
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("page[number]") == "2" {
+			_, _ = fmt.Fprint(w, testdata.CheckRunsPage2Response)
+			return
+		}
+		_, _ = fmt.Fprint(w, testdata.CheckRunsPage1Response)
+	})
+
+	start := time.Date(2020, time.August, 1, 0, 0, 0, 0, time.UTC)
+	got, err := tClient.CheckRuns.Window(100, start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 5)
+	assert.Equal(t, uint(1005), got[4].ID, "runs overlapping the end of the window are kept")
+	assert.Equal(t, CheckFailed, got[1].Result)
+	assert.Equal(t, "yoursite.tld is down: connection timed out", got[1].NotificationMessage)
+
+	timeline := BuildTimeline(got, time.Time{})
+	at := func(min int) time.Time { return start.Add(10*time.Hour + time.Duration(min)*time.Minute) }
+
+	assert.Equal(t, []*StateInterval{
+		{Result: CheckSucceeded, Start: at(0), End: at(1), Runs: 1, Summary: "Up"},
+		{Result: CheckFailed, Start: at(1), End: at(3), Runs: 2, Summary: "Connection timed out"},
+		{Result: CheckSucceeded, Start: at(3), End: start.AddDate(0, 0, 1).Add(10 * time.Second), Runs: 2, Summary: "Up"},
+	}, timeline)
+	assert.True(t, timeline[1].Failing())
+	assert.Equal(t, 2*time.Minute, timeline[1].Duration())
+
+	got, err = tClient.CheckRuns.Window(100, start, start.AddDate(0, 0, 1).Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 6)
+	assert.Equal(t, uint(1006), got[5].ID, "ongoing runs are kept")
+	assert.Nil(t, got[5].EndedAt)
+}
+
+func TestBuildTimeline(t *testing.T) {
+	at := func(min int) *CustomDate {
+		return &CustomDate{time.Date(2020, time.August, 1, 10, min, 0, 0, time.UTC)}
+	}
+
+	runs := []*CheckRun{
+		{Result: CheckErrored, StartedAt: at(5)},
+		{Result: CheckWarning, StartedAt: at(0)},
+		{Result: CheckPending, StartedAt: at(10)},
+		{Result: CheckWarning},
+		nil,
+	}
+
+	until := at(30).Time
+	got := BuildTimeline(runs, until)
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, CheckWarning, got[0].Result)
+	assert.False(t, got[0].Failing())
+	assert.Equal(t, at(5).Time, got[0].End)
+	assert.Equal(t, until, got[1].End)
+	assert.True(t, got[1].Failing())
+
+	single := BuildTimeline([]*CheckRun{{Result: CheckSucceeded, StartedAt: at(5)}}, time.Time{})
+	assert.Equal(t, time.Duration(0), single[0].Duration())
+
+	assert.Nil(t, BuildTimeline(nil, until))
+}
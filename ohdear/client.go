@@ -27,19 +27,42 @@ type Client struct {
 	BaseURL *url.URL
 	client  *http.Client
 	common  srv // Reuse a single struct instead of allocating one for each service on the heap.
-	token   string
+	tokens  TokenSource
 	// Services
-	Sites *SitesSrv
+	Sites                    *SitesSrv
+	ApplicationHealthChecks  *ApplicationHealthChecksSrv
+	BrokenLinks              *BrokenLinksSrv
+	MixedContent             *MixedContentSrv
+	CertificateHealth        *CertificateHealthSrv
+	DetectedCertificates     *DetectedCertificatesSrv
+	NotificationDestinations *NotificationDestinationsSrv
+	Performance              *PerformanceSrv
+	Lighthouse               *LighthouseSrv
+	DNS                      *DNSSrv
+	Domains                  *DomainsSrv
+	Sitemap                  *SitemapSrv
+	Teams                    *TeamsSrv
+	CheckRuns                *CheckRunsSrv
 }
 
 // NewAPIRequest is a wrapper around the http.NewRequest function.
 //
-// It will setup the authentication headers/parameters according to the client config.
+// It will setup the authentication headers/parameters according to the client config,
+// the token source is consulted for every request.
 func (c *Client) NewAPIRequest(method string, uri string, body interface{}) (req *http.Request, err error) {
 	if !strings.HasSuffix(c.BaseURL.Path, "/") {
 		return nil, ErrInvalidBaseURL
 	}
 
+	tkn, err := c.tokens.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if tkn == "" {
+		return nil, ErrEmptyAPIToken
+	}
+
 	u, err := c.BaseURL.Parse(uri)
 	if err != nil {
 		return nil, err
@@ -61,7 +84,7 @@ func (c *Client) NewAPIRequest(method string, uri string, body interface{}) (req
 		return nil, err
 	}
 
-	req.Header.Add(AuthHeader, strings.Join([]string{TokenType, c.token}, " "))
+	req.Header.Add(AuthHeader, strings.Join([]string{TokenType, tkn}, " "))
 	req.Header.Set("Content-Type", ContentExchangeType)
 	req.Header.Set("Accept", ContentExchangeType)
 
@@ -76,6 +99,12 @@ func (c *Client) Do(req *http.Request) (*Response, error) {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		if inv, ok := c.tokens.(TokenInvalidator); ok {
+			inv.Invalidate()
+		}
+	}
+
 	response := newResponse(resp)
 	err = CheckResponse(resp)
 	if err != nil {
@@ -114,10 +143,34 @@ func newResponse(r *http.Response) *Response {
 // NewClient will lookup the environment for values to assign to the
 // API token (`OHDEAR_API_TOKEN`) to be used as authentication.
 func NewClient(baseClient *http.Client, baseURL, apiToken string) (dear *Client, err error) {
+	// Parse authorization from environment
+	// or user provided string.
+	if tkn, ok := os.LookupEnv(APITokenEnv); ok {
+		apiToken = tkn
+	}
+
+	if apiToken == "" {
+		return nil, ErrEmptyAPIToken
+	}
+
+	return NewClientWithTokenSource(baseClient, baseURL, StaticTokenSource(apiToken))
+}
+
+// NewClientWithTokenSource returns a new Oh-Dear HTTP API client which
+// consults the token source every time a request is built, allowing
+// tokens to be rotated without rebuilding the client.
+//
+// Sources implementing TokenInvalidator are invalidated after an
+// unauthorized response.
+func NewClientWithTokenSource(baseClient *http.Client, baseURL string, ts TokenSource) (dear *Client, err error) {
 	if baseClient == nil {
 		baseClient = http.DefaultClient
 	}
 
+	if ts == nil {
+		return nil, ErrEmptyAPIToken
+	}
+
 	var u *url.URL
 	{
 		if baseURL != "" {
@@ -133,24 +186,26 @@ func NewClient(baseClient *http.Client, baseURL, apiToken string) (dear *Client,
 	dear = &Client{
 		BaseURL: u,
 		client:  baseClient,
+		tokens:  ts,
 	}
 
 	dear.common.client = dear
 
 	// services for resources
 	dear.Sites = (*SitesSrv)(&dear.common)
-
-	// Parse authorization from environment
-	// or user provided string.
-	if tkn, ok := os.LookupEnv(APITokenEnv); ok {
-		dear.token = tkn
-	} else {
-		if apiToken == "" {
-			return nil, ErrEmptyAPIToken
-		}
-
-		dear.token = apiToken
-	}
+	dear.ApplicationHealthChecks = (*ApplicationHealthChecksSrv)(&dear.common)
+	dear.BrokenLinks = (*BrokenLinksSrv)(&dear.common)
+	dear.MixedContent = (*MixedContentSrv)(&dear.common)
+	dear.CertificateHealth = (*CertificateHealthSrv)(&dear.common)
+	dear.DetectedCertificates = (*DetectedCertificatesSrv)(&dear.common)
+	dear.NotificationDestinations = (*NotificationDestinationsSrv)(&dear.common)
+	dear.Performance = (*PerformanceSrv)(&dear.common)
+	dear.Lighthouse = (*LighthouseSrv)(&dear.common)
+	dear.DNS = (*DNSSrv)(&dear.common)
+	dear.Domains = (*DomainsSrv)(&dear.common)
+	dear.Sitemap = (*SitemapSrv)(&dear.common)
+	dear.Teams = (*TeamsSrv)(&dear.common)
+	dear.CheckRuns = (*CheckRunsSrv)(&dear.common)
 
 	return
 }
@@ -72,7 +72,8 @@ func TestNewClient(t *testing.T) {
 
 			if !c.wantErr {
 				assert.Nil(tt, err)
-				assert.Equal(tt, c.args.apiToken, got.token)
+				tkn, _ := got.tokens.Token()
+				assert.Equal(tt, c.args.apiToken, tkn)
 			} else if c.wantErr {
 				assert.EqualError(tt, err, c.err.Error())
 			}
@@ -89,7 +90,8 @@ func TestNewClient_TokenFromEnvironment(t *testing.T) {
 	got, err := NewClient(nil, "", "")
 
 	assert.Nil(t, err)
-	assert.Equal(t, testTkn, got.token)
+	tkn, _ := got.tokens.Token()
+	assert.Equal(t, testTkn, tkn)
 }
 
 func TestClient_NewAPIRequest(t *testing.T) {
@@ -253,3 +255,17 @@ func TestClient_Do_ResponseErr(t *testing.T) {
 
 	assert.EqualError(t, err, "response failed with status 404|404 Not Found")
 }
+
+func TestClient_NewAPIRequest_KeepsBaseURLPath(t *testing.T) {
+	c, err := NewClient(nil, BaseURL, testTkn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := c.NewAPIRequest(http.MethodGet, fmt.Sprintf("%s/%d", SitesBasePath, 1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "https://ohdear.app/api/sites/1", req.URL.String())
+}
@@ -0,0 +1,99 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+)
+
+// DetectedCertificatesPath is the resource path appended to a site.
+const DetectedCertificatesPath string = "detected-certificates"
+
+// DetectedCertificatesSrv operates over the certificates found in
+// certificate transparency logs for the domain of a site.
+type DetectedCertificatesSrv srv
+
+// DetectedCertificate describes a certificate issued for the
+// domain of a site and the moment it was detected.
+type DetectedCertificate struct {
+	ID          uint                `json:"id,omitempty"`
+	Fingerprint string              `json:"fingerprint,omitempty"`
+	Details     *CertificateDetails `json:"certificate_details,omitempty"`
+	CreatedAt   *CustomDate         `json:"created_at,omitempty"`
+}
+
+// DetectedCertificatesResponse is a page of detected certificates
+// inside an outer data wrapper.
+type DetectedCertificatesResponse struct {
+	Data  []*DetectedCertificate `json:"data"`
+	Links *PaginationLinks       `json:"links,omitempty"`
+	Meta  *PaginationMeta        `json:"meta,omitempty"`
+}
+
+// DetectedCertificatesRequestFilters adds the query string parameters
+// to paginate and filter detected certificates by detection date.
+// The specified dates should be represented as follows:
+// 20200801000000.
+//
+// None of the values are required.
+type DetectedCertificatesRequestFilters struct {
+	PageSize   uint   `url:"page[size],omitempty"`
+	PageNumber uint   `url:"page[number],omitempty"`
+	StartedAt  string `url:"filter[started_at],omitempty"`
+	EndedAt    string `url:"filter[ended_at],omitempty"`
+}
+
+// List returns the certificates detected for a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#certificate-transparency
+func (dcs *DetectedCertificatesSrv) List(siteID uint, filters DetectedCertificatesRequestFilters) (dr *DetectedCertificatesResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := dcs.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s?%s", SitesBasePath, siteID, DetectedCertificatesPath, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := dcs.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &dr); err != nil {
+		return
+	}
+
+	return
+}
+
+// UnexpectedIssuers returns the certificates whose issuer is not
+// part of the allowed issuers, issuers are compared case insensitively.
+func UnexpectedIssuers(certs []*DetectedCertificate, allowed []string) (unexpected []*DetectedCertificate) {
+	allow := make(map[string]struct{}, len(allowed))
+	for _, a := range allowed {
+		allow[normalizeIssuer(a)] = struct{}{}
+	}
+
+	for _, c := range certs {
+		var issuer string
+		if c.Details != nil {
+			issuer = c.Details.Issuer
+		}
+
+		if _, ok := allow[normalizeIssuer(issuer)]; !ok {
+			unexpected = append(unexpected, c)
+		}
+	}
+
+	return
+}
+
+func normalizeIssuer(issuer string) string {
+	return strings.ToLower(strings.TrimSpace(issuer))
+}
@@ -0,0 +1,53 @@
+package ohdear
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestDetectedCertificatesSrv_List(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	since := FilterDate(time.Date(2019, time.August, 1, 0, 0, 0, 0, time.UTC))
+
+	tMux.HandleFunc("/sites/2/detected-certificates", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "20190801000000", r.URL.Query().Get("filter[started_at]"))
+		assert.Empty(t, r.URL.Query().Get("filter[ended_at]"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.DetectedCertificatesResponse)
+	})
+
+	got, err := tClient.DetectedCertificates.List(2, DetectedCertificatesRequestFilters{StartedAt: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 2)
+	assert.Equal(t, "Let's Encrypt Authority X3", got.Data[0].Details.Issuer)
+	assert.Equal(t, 28, got.Data[0].CreatedAt.Day())
+}
+
+func TestUnexpectedIssuers(t *testing.T) {
+	le := &DetectedCertificate{ID: 1, Details: &CertificateDetails{Issuer: "Let's Encrypt Authority X3"}}
+	rogue := &DetectedCertificate{ID: 2, Details: &CertificateDetails{Issuer: "Unknown Issuing CA"}}
+	empty := &DetectedCertificate{ID: 3}
+
+	got := UnexpectedIssuers([]*DetectedCertificate{le, rogue, empty}, []string{" let's encrypt authority x3"})
+
+	assert.Equal(t, []*DetectedCertificate{rogue, empty}, got)
+	assert.Nil(t, UnexpectedIssuers([]*DetectedCertificate{le}, []string{"Let's Encrypt Authority X3"}))
+}
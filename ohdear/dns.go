@@ -0,0 +1,346 @@
+package ohdear
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+)
+
+// DNSHistoryItemsPath is the resource path appended to a site.
+const DNSHistoryItemsPath string = "dns-history-items"
+
+// DNSSrv operates over the DNS records monitored for a site.
+type DNSSrv srv
+
+// DNSRecordType is the type of a DNS record.
+type DNSRecordType string
+
+// Supported DNSRecordType values.
+const (
+	DNSRecordA     DNSRecordType = "A"
+	DNSRecordAAAA  DNSRecordType = "AAAA"
+	DNSRecordCNAME DNSRecordType = "CNAME"
+	DNSRecordMX    DNSRecordType = "MX"
+	DNSRecordNS    DNSRecordType = "NS"
+	DNSRecordTXT   DNSRecordType = "TXT"
+	DNSRecordSOA   DNSRecordType = "SOA"
+	DNSRecordCAA   DNSRecordType = "CAA"
+)
+
+// DNSRecord is a single resource record, only the fields
+// of its type are populated.
+type DNSRecord struct {
+	Host  string        `json:"host"`
+	Class string        `json:"class,omitempty"`
+	TTL   uint          `json:"ttl"`
+	Type  DNSRecordType `json:"type"`
+	// A and AAAA records.
+	IP   string `json:"ip,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+	// CNAME, MX and NS records.
+	Target string `json:"target,omitempty"`
+	Pri    uint   `json:"pri,omitempty"`
+	// TXT records.
+	Txt string `json:"txt,omitempty"`
+	// SOA records.
+	MName      string `json:"mname,omitempty"`
+	RName      string `json:"rname,omitempty"`
+	Serial     uint   `json:"serial,omitempty"`
+	Refresh    uint   `json:"refresh,omitempty"`
+	Retry      uint   `json:"retry,omitempty"`
+	Expire     uint   `json:"expire,omitempty"`
+	MinimumTTL uint   `json:"minimum_ttl,omitempty"`
+	// CAA records.
+	Flags uint   `json:"flags,omitempty"`
+	Tag   string `json:"tag,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// Data returns the record data in zone file notation, without
+// the owner, class and ttl.
+//
+// Records of other types, e.g. SRV or PTR, are encoded as their
+// non empty fields sorted by name, e.g. "pri=10 target=sip.example.net".
+func (r *DNSRecord) Data() string {
+	switch r.Type {
+	case DNSRecordA:
+		return r.IP
+	case DNSRecordAAAA:
+		return r.IPv6
+	case DNSRecordCNAME, DNSRecordNS:
+		return r.Target
+	case DNSRecordMX:
+		return fmt.Sprintf("%d %s", r.Pri, r.Target)
+	case DNSRecordTXT:
+		return r.Txt
+	case DNSRecordSOA:
+		return fmt.Sprintf("%s %s %d %d %d %d %d", r.MName, r.RName, r.Serial, r.Refresh, r.Retry, r.Expire, r.MinimumTTL)
+	case DNSRecordCAA:
+		return fmt.Sprintf("%d %s %q", r.Flags, r.Tag, r.Value)
+	}
+
+	return r.fields()
+}
+
+// fields encodes the data fields of the record as sorted
+// key=value pairs.
+func (r *DNSRecord) fields() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return ""
+	}
+
+	for _, k := range []string{"host", "class", "ttl", "type"} {
+		delete(fields, k)
+	}
+
+	pairs := make([]string, 0, len(fields))
+	for k, v := range fields {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, " ")
+}
+
+// String returns the record in zone file notation.
+func (r *DNSRecord) String() string {
+	return fmt.Sprintf("%s %d %s %s", r.Host, r.TTL, r.Type, r.Data())
+}
+
+// DNSSnapshot is the state of the DNS records of a site at a point in time.
+type DNSSnapshot struct {
+	ID                       uint         `json:"id,omitempty"`
+	AuthoritativeNameservers []string     `json:"authoritative_nameservers,omitempty"`
+	DNSRecords               []*DNSRecord `json:"dns_records,omitempty"`
+	CreatedAt                *CustomDate  `json:"created_at,omitempty"`
+}
+
+// DNSHistoryResponse is a page of DNS snapshots, newest first,
+// inside an outer data wrapper.
+type DNSHistoryResponse struct {
+	Data  []*DNSSnapshot   `json:"data"`
+	Links *PaginationLinks `json:"links,omitempty"`
+	Meta  *PaginationMeta  `json:"meta,omitempty"`
+}
+
+// DNSRequestFilters adds the pagination parameters to DNS requests.
+//
+// None of the values are required.
+type DNSRequestFilters struct {
+	PageSize   uint `url:"page[size],omitempty"`
+	PageNumber uint `url:"page[number],omitempty"`
+}
+
+// History returns the DNS snapshots recorded every time
+// the records of a site changed.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#dns-history
+func (ds *DNSSrv) History(siteID uint, filters DNSRequestFilters) (dr *DNSHistoryResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := ds.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s?%s", SitesBasePath, siteID, DNSHistoryItemsPath, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ds.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &dr); err != nil {
+		return
+	}
+
+	return
+}
+
+// Get returns a specific DNS snapshot of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#dns-history
+func (ds *DNSSrv) Get(siteID, snapshotID uint) (snapshot *DNSSnapshot, err error) {
+	req, err := ds.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s/%d", SitesBasePath, siteID, DNSHistoryItemsPath, snapshotID),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ds.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &snapshot); err != nil {
+		return
+	}
+
+	return
+}
+
+// Current returns the latest DNS snapshot of a site, a nil
+// snapshot is returned when no records were collected yet.
+func (ds *DNSSrv) Current(siteID uint) (snapshot *DNSSnapshot, err error) {
+	dr, err := ds.History(siteID, DNSRequestFilters{PageSize: 1, PageNumber: 1})
+	if err != nil {
+		return
+	}
+
+	if len(dr.Data) > 0 {
+		snapshot = dr.Data[0]
+	}
+
+	return
+}
+
+// DNSRecordChange holds the previous and the new version of a record.
+type DNSRecordChange struct {
+	Old *DNSRecord `json:"old"`
+	New *DNSRecord `json:"new"`
+}
+
+// DNSDiff lists the differences between two sets of DNS records.
+type DNSDiff struct {
+	Added   []*DNSRecord       `json:"added,omitempty"`
+	Removed []*DNSRecord       `json:"removed,omitempty"`
+	Changed []*DNSRecordChange `json:"changed,omitempty"`
+}
+
+// Empty reports whether both sets of records are equivalent.
+func (d *DNSDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffDNSSnapshots compares the records of two snapshots,
+// nil snapshots have no records.
+func DiffDNSSnapshots(before, after *DNSSnapshot) *DNSDiff {
+	var br, ar []*DNSRecord
+	if before != nil {
+		br = before.DNSRecords
+	}
+
+	if after != nil {
+		ar = after.DNSRecords
+	}
+
+	return DiffDNSRecords(br, ar)
+}
+
+// DiffDNSRecords compares two sets of records sharing the same type
+// and host, hosts are compared without case and trailing dot.
+//
+// Records with the same data and a different ttl are changed, and so is
+// the only record of a type and host replaced by a single new one, like
+// an A record pointing to a new address or a SOA with a new serial.
+// Every other difference is reported as added or removed records.
+//
+// The results are sorted by type, host and data.
+func DiffDNSRecords(before, after []*DNSRecord) *DNSDiff {
+	type key struct {
+		typ  DNSRecordType
+		host string
+	}
+
+	group := func(records []*DNSRecord) map[key][]*DNSRecord {
+		g := make(map[key][]*DNSRecord)
+		for _, r := range records {
+			if r == nil {
+				continue
+			}
+
+			k := key{r.Type, strings.TrimSuffix(strings.ToLower(r.Host), ".")}
+			g[k] = append(g[k], r)
+		}
+
+		return g
+	}
+
+	og, ng := group(before), group(after)
+	keys := make(map[key]bool, len(og)+len(ng))
+	for k := range og {
+		keys[k] = true
+	}
+
+	for k := range ng {
+		keys[k] = true
+	}
+
+	diff := &DNSDiff{}
+	for k := range keys {
+		var removed []*DNSRecord
+		added := append([]*DNSRecord(nil), ng[k]...)
+
+		for _, o := range og[k] {
+			match := -1
+			for i, n := range added {
+				if n.Data() == o.Data() {
+					match = i
+					break
+				}
+			}
+
+			if match < 0 {
+				removed = append(removed, o)
+				continue
+			}
+
+			if n := added[match]; n.TTL != o.TTL {
+				diff.Changed = append(diff.Changed, &DNSRecordChange{Old: o, New: n})
+			}
+
+			added = append(added[:match], added[match+1:]...)
+		}
+
+		if len(removed) == 1 && len(added) == 1 {
+			diff.Changed = append(diff.Changed, &DNSRecordChange{Old: removed[0], New: added[0]})
+			continue
+		}
+
+		diff.Removed = append(diff.Removed, removed...)
+		diff.Added = append(diff.Added, added...)
+	}
+
+	sortDNSRecords(diff.Added)
+	sortDNSRecords(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return dnsRecordLess(diff.Changed[i].Old, diff.Changed[j].Old)
+	})
+
+	return diff
+}
+
+func sortDNSRecords(records []*DNSRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		return dnsRecordLess(records[i], records[j])
+	})
+}
+
+func dnsRecordLess(a, b *DNSRecord) bool {
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+
+	if a.Host != b.Host {
+		return a.Host < b.Host
+	}
+
+	return a.Data() < b.Data()
+}
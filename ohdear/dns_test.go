@@ -0,0 +1,159 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestDNSSrv_History(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/dns-history-items", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.DNSHistoryResponse)
+	})
+
+	got, err := tClient.DNS.History(1, DNSRequestFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 2)
+	assert.Len(t, got.Data[0].DNSRecords, 8)
+	assert.Equal(t, []string{"ns1.example.net", "ns2.example.net"}, got.Data[0].AuthoritativeNameservers)
+
+	records := got.Data[0].DNSRecords
+	assert.Equal(t, "yoursite.tld 300 A 203.0.113.20", records[0].String())
+	assert.Equal(t, "2001:db8::20", records[1].Data())
+	assert.Equal(t, "10 mx1.example.net", records[3].Data())
+	assert.Equal(t, "ns1.example.net hostmaster.yoursite.tld 2020080201 7200 3600 1209600 300", records[6].Data())
+	assert.Equal(t, `0 issue "letsencrypt.org"`, records[7].Data())
+}
+
+func TestDNSSrv_Current(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/dns-history-items", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "1", r.URL.Query().Get("page[size]"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.DNSHistoryResponse)
+	})
+
+	tMux.HandleFunc("/sites/2/dns-history-items", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	got, err := tClient.DNS.Current(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint(8), got.ID)
+
+	got, err = tClient.DNS.Current(2)
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+}
+
+func TestDNSSrv_Get(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/dns-history-items/7", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.DNSSnapshotResponse)
+	})
+
+	got, err := tClient.DNS.Get(1, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "203.0.113.10", got.DNSRecords[0].IP)
+	assert.Equal(t, 1, got.CreatedAt.Day())
+}
+
+func TestDiffDNSSnapshots(t *testing.T) {
+	var history DNSHistoryResponse
+	if err := json.Unmarshal([]byte(testdata.DNSHistoryResponse), &history); err != nil {
+		t.Fatal(err)
+	}
+
+	newer, older := history.Data[0], history.Data[1]
+	diff := DiffDNSSnapshots(older, newer)
+
+	assert.False(t, diff.Empty())
+	assert.Empty(t, diff.Added)
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, "20 mx2.example.net", diff.Removed[0].Data())
+
+	assert.Len(t, diff.Changed, 3)
+	assert.Equal(t, "203.0.113.10", diff.Changed[0].Old.IP)
+	assert.Equal(t, "203.0.113.20", diff.Changed[0].New.IP)
+	assert.Equal(t, DNSRecordNS, diff.Changed[1].Old.Type)
+	assert.Equal(t, uint(86400), diff.Changed[1].New.TTL)
+	assert.Equal(t, uint(2020080201), diff.Changed[2].New.Serial)
+
+	assert.True(t, DiffDNSSnapshots(newer, newer).Empty())
+	assert.Len(t, DiffDNSSnapshots(nil, newer).Added, 8)
+}
+
+func TestDiffDNSRecords(t *testing.T) {
+	old := []*DNSRecord{
+		{Host: "yoursite.tld", Type: DNSRecordA, TTL: 60, IP: "203.0.113.1"},
+		{Host: "yoursite.tld", Type: DNSRecordA, TTL: 60, IP: "203.0.113.2"},
+		{Host: "yoursite.tld", Type: DNSRecordTXT, TTL: 60, Txt: "a"},
+	}
+	updated := []*DNSRecord{
+		{Host: "YourSite.tld.", Type: DNSRecordA, TTL: 60, IP: "203.0.113.3"},
+		{Host: "yoursite.tld", Type: DNSRecordA, TTL: 60, IP: "203.0.113.4"},
+		{Host: "yoursite.tld", Type: DNSRecordTXT, TTL: 60, Txt: "a"},
+		nil,
+	}
+
+	diff := DiffDNSRecords(old, updated)
+
+	assert.Empty(t, diff.Changed)
+	assert.Equal(t, []*DNSRecord{updated[0], updated[1]}, diff.Added)
+	assert.Equal(t, []*DNSRecord{old[0], old[1]}, diff.Removed)
+}
+
+func TestDNSRecord_DataOfOtherTypes(t *testing.T) {
+	srv := &DNSRecord{Host: "_sip._tcp.yoursite.tld", TTL: 300, Type: "SRV", Pri: 10, Target: "sip.example.net"}
+	assert.Equal(t, "pri=10 target=sip.example.net", srv.Data())
+	assert.Equal(t, "_sip._tcp.yoursite.tld 300 SRV pri=10 target=sip.example.net", srv.String())
+
+	ptr := &DNSRecord{Host: "20.113.0.203.in-addr.arpa", TTL: 300, Type: "PTR", Target: "yoursite.tld", Serial: 2020080201}
+	assert.Equal(t, "serial=2020080201 target=yoursite.tld", ptr.Data())
+
+	diff := DiffDNSRecords(
+		[]*DNSRecord{{Host: "yoursite.tld", Type: "SRV", Target: "a.example.net"}},
+		[]*DNSRecord{{Host: "yoursite.tld", Type: "SRV", Target: "b.example.net"}},
+	)
+	assert.Len(t, diff.Changed, 1, "records of other types with different fields are not equal")
+}
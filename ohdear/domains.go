@@ -0,0 +1,141 @@
+package ohdear
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DomainPath is the resource path appended to a site.
+const DomainPath string = "domain"
+
+// DomainsSrv operates over the domain registration monitoring of a site.
+type DomainsSrv srv
+
+// Domain describes the registration of the domain of a site as
+// reported by WHOIS or RDAP, Source tells which one was used.
+type Domain struct {
+	Domain       string      `json:"domain,omitempty"`
+	Registrar    string      `json:"registrar,omitempty"`
+	RegisteredAt *CustomDate `json:"registered_at,omitempty"`
+	ExpiresAt    *CustomDate `json:"expires_at,omitempty"`
+	Nameservers  []string    `json:"nameservers,omitempty"`
+	StatusCodes  []string    `json:"status_codes,omitempty"`
+	Source       string      `json:"source,omitempty"`
+	CheckedAt    *CustomDate `json:"checked_at,omitempty"`
+}
+
+// ExpiringDomain relates a site with its soon to expire domain.
+type ExpiringDomain struct {
+	Site   *Site
+	Domain *Domain
+}
+
+// DaysUntilExpiry returns the amount of whole days left before the
+// domain registration expires, rounded down so a domain which just
+// expired reports -1.
+//
+// It returns 0 when the expiry date is unknown.
+func (d *Domain) DaysUntilExpiry() int {
+	if !d.hasExpiry() {
+		return 0
+	}
+
+	return daysUntil(d.ExpiresAt.Time)
+}
+
+func (d *Domain) hasExpiry() bool {
+	return d != nil && d.ExpiresAt != nil
+}
+
+// HasStatus reports whether the registry returned the status code,
+// codes are compared without case and spaces so "clientTransferProhibited"
+// matches the RDAP "client transfer prohibited".
+func (d *Domain) HasStatus(code string) bool {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+
+	code = normalize(code)
+	for _, s := range d.StatusCodes {
+		if normalize(s) == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get retrieves the domain registration details of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#domain-monitoring
+func (ds *DomainsSrv) Get(siteID uint) (*Domain, error) {
+	return ds.get(context.Background(), siteID)
+}
+
+func (ds *DomainsSrv) get(ctx context.Context, siteID uint) (d *Domain, err error) {
+	req, err := ds.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s", SitesBasePath, siteID, DomainPath),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ds.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &d); err != nil {
+		return
+	}
+
+	return
+}
+
+// ExpiringWithin walks all the sites returned by SitesSrv.ListAll and
+// returns the ones whose domain expires before the given duration
+// elapses, the most urgent first.
+//
+// Sites without domain monitoring, answered with a not found
+// status, are skipped.
+func (ds *DomainsSrv) ExpiringWithin(ctx context.Context, d time.Duration) (expiring []*ExpiringDomain, err error) {
+	sites, err := ds.client.Sites.ListAll(ctx, ListSitesRequestFilters{})
+	if err != nil {
+		return
+	}
+
+	deadline := time.Now().Add(d)
+	for _, s := range sites {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		dm, err := ds.get(ctx, s.ID)
+		if err != nil {
+			var apiErr *Error
+			if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if dm.hasExpiry() && dm.ExpiresAt.Before(deadline) {
+			expiring = append(expiring, &ExpiringDomain{Site: s, Domain: dm})
+		}
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].Domain.ExpiresAt.Before(expiring[j].Domain.ExpiresAt.Time)
+	})
+
+	return
+}
@@ -0,0 +1,121 @@
+package ohdear
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestDomainsSrv_Get(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/domain", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.DomainResponse)
+	})
+
+	got, err := tClient.Domains.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Example Registrar, Inc.", got.Registrar)
+	assert.Equal(t, 2021, got.ExpiresAt.Year())
+	assert.Equal(t, []string{"ns1.example.net", "ns2.example.net"}, got.Nameservers)
+	assert.Equal(t, "rdap", got.Source)
+	assert.True(t, got.HasStatus("clientTransferProhibited"))
+	assert.False(t, got.HasStatus("serverHold"))
+}
+
+func TestDomain_DaysUntilExpiry(t *testing.T) {
+	in := func(d time.Duration) *Domain {
+		return &Domain{ExpiresAt: &CustomDate{time.Now().Add(d)}}
+	}
+
+	assert.Equal(t, 10, in(10*24*time.Hour+time.Hour).DaysUntilExpiry())
+	assert.Equal(t, 0, in(time.Hour).DaysUntilExpiry())
+	assert.Equal(t, -1, in(-time.Hour).DaysUntilExpiry(), "just expired domains are not reported as unknown")
+	assert.Equal(t, -2, in(-2*24*time.Hour+time.Hour).DaysUntilExpiry())
+	assert.Equal(t, 0, (&Domain{}).DaysUntilExpiry())
+}
+
+func TestDomainsSrv_ExpiringWithin(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	in := func(d time.Duration) string {
+		return strings.Replace(testdata.DomainResponse, "2021-03-14 09:21:00", time.Now().UTC().Add(d).Format(CustomDateLayout), 1)
+	}
+
+	monitored := true
+	tMux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[number]") == "2" {
+			_, _ = fmt.Fprint(w, testdata.SitesLastPageResponse)
+			return
+		}
+		_, _ = fmt.Fprint(w, testdata.SitesFirstPageResponse)
+	})
+	tMux.HandleFunc("/sites/1/domain", func(w http.ResponseWriter, r *http.Request) {
+		if !monitored {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, in(20*24*time.Hour))
+	})
+	tMux.HandleFunc("/sites/2/domain", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, in(5*24*time.Hour))
+	})
+	tMux.HandleFunc("/sites/3/domain", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, in(2*24*time.Hour))
+	})
+
+	got, err := tClient.Domains.ExpiringWithin(context.Background(), 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 3)
+	assert.Equal(t, uint(3), got[0].Site.ID, "sites of the following pages are inspected")
+	assert.Equal(t, uint(2), got[1].Site.ID)
+	assert.Equal(t, uint(1), got[2].Site.ID)
+
+	got, err = tClient.Domains.ExpiringWithin(context.Background(), 10*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 2)
+
+	monitored = false
+	got, err = tClient.Domains.ExpiringWithin(context.Background(), 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, uint(3), got[0].Site.ID)
+	assert.Equal(t, uint(2), got[1].Site.ID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tClient.Domains.ExpiringWithin(ctx, 24*time.Hour)
+	assert.Equal(t, context.Canceled, err)
+}
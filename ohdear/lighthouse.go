@@ -0,0 +1,199 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+// LighthouseReportsPath is the resource path appended to a site.
+const LighthouseReportsPath string = "lighthouse-reports"
+
+// LighthouseSrv operates over the Lighthouse reports of a site.
+type LighthouseSrv srv
+
+// LighthouseReport holds the category scores, from 0 to 100, and the
+// core web vitals measured by a Lighthouse run.
+type LighthouseReport struct {
+	ID                         uint        `json:"id,omitempty"`
+	PerformanceScore           int         `json:"performance_score"`
+	AccessibilityScore         int         `json:"accessibility_score"`
+	BestPracticesScore         int         `json:"best_practices_score"`
+	SEOScore                   int         `json:"seo_score"`
+	ProgressiveWebAppScore     int         `json:"progressive_web_app_score"`
+	FirstContentfulPaintInMs   float64     `json:"first_contentful_paint_in_ms"`
+	LargestContentfulPaintInMs float64     `json:"largest_contentful_paint_in_ms"`
+	SpeedIndexInMs             float64     `json:"speed_index_in_ms"`
+	TimeToInteractiveInMs      float64     `json:"time_to_interactive_in_ms"`
+	TotalBlockingTimeInMs      float64     `json:"total_blocking_time_in_ms"`
+	CumulativeLayoutShift      float64     `json:"cumulative_layout_shift"`
+	PerformedOnCheckerServer   string      `json:"performed_on_checker_server,omitempty"`
+	CreatedAt                  *CustomDate `json:"created_at,omitempty"`
+}
+
+// LighthouseReportsResponse is a page of Lighthouse reports
+// inside an outer data wrapper.
+type LighthouseReportsResponse struct {
+	Data  []*LighthouseReport `json:"data"`
+	Links *PaginationLinks    `json:"links,omitempty"`
+	Meta  *PaginationMeta     `json:"meta,omitempty"`
+}
+
+// LighthouseRequestFilters adds the pagination parameters
+// to Lighthouse requests.
+//
+// None of the values are required.
+type LighthouseRequestFilters struct {
+	PageSize   uint `url:"page[size],omitempty"`
+	PageNumber uint `url:"page[number],omitempty"`
+}
+
+// List returns the Lighthouse reports of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#lighthouse
+func (ls *LighthouseSrv) List(siteID uint, filters LighthouseRequestFilters) (lr *LighthouseReportsResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := ls.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s?%s", SitesBasePath, siteID, LighthouseReportsPath, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ls.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &lr); err != nil {
+		return
+	}
+
+	return
+}
+
+// Latest returns the most recent Lighthouse report of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#lighthouse
+func (ls *LighthouseSrv) Latest(siteID uint) (report *LighthouseReport, err error) {
+	return ls.get(fmt.Sprintf("%s/%d/%s/latest", SitesBasePath, siteID, LighthouseReportsPath))
+}
+
+// Get returns a specific Lighthouse report of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#lighthouse
+func (ls *LighthouseSrv) Get(siteID, reportID uint) (report *LighthouseReport, err error) {
+	return ls.get(fmt.Sprintf("%s/%d/%s/%d", SitesBasePath, siteID, LighthouseReportsPath, reportID))
+}
+
+func (ls *LighthouseSrv) get(uri string) (report *LighthouseReport, err error) {
+	req, err := ls.client.NewAPIRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return
+	}
+
+	res, err := ls.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &report); err != nil {
+		return
+	}
+
+	return
+}
+
+// LighthouseThresholds are the tolerated changes between two reports.
+//
+// ScorePoints is the drop of a category score, in points, and
+// MetricPercent the relative increase of a web vital, in percent,
+// above which a change is reported as a regression.
+//
+// A relative increase can not be computed for a web vital which is
+// zero in the baseline, its increase is compared with the limit of
+// MetricAbsolute, keyed by metric name and expressed in the unit of
+// the metric, e.g. {"cumulative_layout_shift": 0.1}. Web vitals
+// without limit regress as soon as they increase.
+type LighthouseThresholds struct {
+	ScorePoints    int
+	MetricPercent  float64
+	MetricAbsolute map[string]float64
+}
+
+// LighthouseRegression describes a score or web vital which got worse
+// between two reports. Delta is expressed in points for the scores, in
+// percent for the web vitals and in the unit of the web vital when
+// its baseline is zero.
+type LighthouseRegression struct {
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Delta    float64 `json:"delta"`
+}
+
+// lighthouseValue reads a score or web vital from a report.
+type lighthouseValue struct {
+	metric string
+	value  func(r *LighthouseReport) float64
+	score  bool
+}
+
+var lighthouseValues = []lighthouseValue{
+	{"performance_score", func(r *LighthouseReport) float64 { return float64(r.PerformanceScore) }, true},
+	{"accessibility_score", func(r *LighthouseReport) float64 { return float64(r.AccessibilityScore) }, true},
+	{"best_practices_score", func(r *LighthouseReport) float64 { return float64(r.BestPracticesScore) }, true},
+	{"seo_score", func(r *LighthouseReport) float64 { return float64(r.SEOScore) }, true},
+	{"first_contentful_paint_in_ms", func(r *LighthouseReport) float64 { return r.FirstContentfulPaintInMs }, false},
+	{"largest_contentful_paint_in_ms", func(r *LighthouseReport) float64 { return r.LargestContentfulPaintInMs }, false},
+	{"speed_index_in_ms", func(r *LighthouseReport) float64 { return r.SpeedIndexInMs }, false},
+	{"time_to_interactive_in_ms", func(r *LighthouseReport) float64 { return r.TimeToInteractiveInMs }, false},
+	{"total_blocking_time_in_ms", func(r *LighthouseReport) float64 { return r.TotalBlockingTimeInMs }, false},
+	{"cumulative_layout_shift", func(r *LighthouseReport) float64 { return r.CumulativeLayoutShift }, false},
+}
+
+// CompareLighthouseReports returns the scores which dropped and the web
+// vitals which increased above the thresholds between the baseline
+// and the current report.
+//
+// The regressions follow the order of the report fields.
+func CompareLighthouseReports(baseline, current *LighthouseReport, th LighthouseThresholds) (regressions []*LighthouseRegression) {
+	if baseline == nil || current == nil {
+		return
+	}
+
+	for _, lv := range lighthouseValues {
+		b, c := lv.value(baseline), lv.value(current)
+
+		var delta float64
+		if lv.score {
+			delta = b - c
+			if delta <= float64(th.ScorePoints) {
+				continue
+			}
+		} else if b <= 0 {
+			delta = c - b
+			if delta <= th.MetricAbsolute[lv.metric] {
+				continue
+			}
+		} else {
+			delta = 100 * (c - b) / b
+			if delta <= th.MetricPercent {
+				continue
+			}
+		}
+
+		regressions = append(regressions, &LighthouseRegression{
+			Metric:   lv.metric,
+			Baseline: b,
+			Current:  c,
+			Delta:    delta,
+		})
+	}
+
+	return
+}
@@ -0,0 +1,139 @@
+package ohdear
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestLighthouseSrv_List(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/lighthouse-reports", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "2", r.URL.Query().Get("page[size]"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.LighthouseReportsResponse)
+	})
+
+	got, err := tClient.Lighthouse.List(1, LighthouseRequestFilters{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 2)
+	assert.Equal(t, 91, got.Data[0].PerformanceScore)
+	assert.Equal(t, 1530.2, got.Data[0].LargestContentfulPaintInMs)
+	assert.False(t, got.Meta.HasNextPage())
+}
+
+func TestLighthouseSrv_Latest(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/lighthouse-reports/latest", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.LighthouseReportResponse)
+	})
+
+	got, err := tClient.Lighthouse.Latest(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint(12), got.ID)
+	assert.Equal(t, "Frankfurt", got.PerformedOnCheckerServer)
+}
+
+func TestLighthouseSrv_Get(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/lighthouse-reports/12", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.LighthouseReportResponse)
+	})
+
+	got, err := tClient.Lighthouse.Get(1, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 0.02, got.CumulativeLayoutShift)
+	assert.Equal(t, 2, got.CreatedAt.Day())
+}
+
+func TestCompareLighthouseReports(t *testing.T) {
+	baseline := &LighthouseReport{
+		PerformanceScore:           95,
+		AccessibilityScore:         98,
+		SEOScore:                   90,
+		LargestContentfulPaintInMs: 1200,
+		TotalBlockingTimeInMs:      40,
+	}
+	current := &LighthouseReport{
+		PerformanceScore:           91,
+		AccessibilityScore:         99,
+		SEOScore:                   88,
+		LargestContentfulPaintInMs: 1530,
+		TotalBlockingTimeInMs:      42,
+		CumulativeLayoutShift:      0.3,
+	}
+
+	got := CompareLighthouseReports(baseline, current, LighthouseThresholds{
+		ScorePoints:    2,
+		MetricPercent:  10,
+		MetricAbsolute: map[string]float64{"cumulative_layout_shift": 0.5},
+	})
+
+	assert.Equal(t, []*LighthouseRegression{
+		{Metric: "performance_score", Baseline: 95, Current: 91, Delta: 4},
+		{Metric: "largest_contentful_paint_in_ms", Baseline: 1200, Current: 1530, Delta: 27.5},
+	}, got)
+
+	assert.Len(t, CompareLighthouseReports(baseline, current, LighthouseThresholds{}), 5)
+	assert.Nil(t, CompareLighthouseReports(baseline, baseline, LighthouseThresholds{}))
+	assert.Nil(t, CompareLighthouseReports(nil, current, LighthouseThresholds{}))
+}
+
+func TestCompareLighthouseReports_ZeroBaseline(t *testing.T) {
+	baseline := &LighthouseReport{PerformanceScore: 100}
+	current := &LighthouseReport{
+		PerformanceScore:      100,
+		TotalBlockingTimeInMs: 30,
+		CumulativeLayoutShift: 0.05,
+	}
+
+	got := CompareLighthouseReports(baseline, current, LighthouseThresholds{
+		MetricPercent: 10,
+		MetricAbsolute: map[string]float64{
+			"total_blocking_time_in_ms": 10,
+			"cumulative_layout_shift":   0.1,
+		},
+	})
+
+	assert.Equal(t, []*LighthouseRegression{
+		{Metric: "total_blocking_time_in_ms", Baseline: 0, Current: 30, Delta: 30},
+	}, got)
+}
@@ -0,0 +1,178 @@
+package ohdear
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/google/go-querystring/query"
+)
+
+// MixedContentBasePath is the resource path prefix.
+const MixedContentBasePath string = "mixed-content"
+
+// MixedContentSrv operates over the mixed content detected on a site.
+type MixedContentSrv srv
+
+// MixedContent describes an insecure element loaded
+// by a page served over https.
+type MixedContent struct {
+	ElementName     string `json:"element_name,omitempty"`
+	MixedContentURL string `json:"mixed_content_url,omitempty"`
+	FoundOnURL      string `json:"found_on_url,omitempty"`
+}
+
+// Origin returns the scheme and host serving the insecure element.
+func (mc *MixedContent) Origin() string {
+	u, err := url.Parse(mc.MixedContentURL)
+	if err != nil || u.Host == "" {
+		return mc.MixedContentURL
+	}
+
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+}
+
+// MixedContentResponse is a page of mixed content findings
+// inside an outer data wrapper.
+type MixedContentResponse struct {
+	Data  []*MixedContent  `json:"data"`
+	Links *PaginationLinks `json:"links,omitempty"`
+	Meta  *PaginationMeta  `json:"meta,omitempty"`
+}
+
+// MixedContentRequestFilters adds the pagination parameters
+// to mixed content requests.
+//
+// None of the values are required.
+type MixedContentRequestFilters struct {
+	PageSize   uint `url:"page[size],omitempty"`
+	PageNumber uint `url:"page[number],omitempty"`
+}
+
+// MixedContentGroup gathers the findings sharing the same
+// element name and origin across one or many sites.
+type MixedContentGroup struct {
+	ElementName string
+	Origin      string
+	SiteIDs     []uint
+	Findings    []*MixedContent
+}
+
+// List returns the mixed content detected for a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#mixed-content
+func (mcs *MixedContentSrv) List(siteID uint, filters MixedContentRequestFilters) (mr *MixedContentResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := mcs.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d?%s", MixedContentBasePath, siteID, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := mcs.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &mr); err != nil {
+		return
+	}
+
+	return
+}
+
+// ListAll walks every page of mixed content findings of a site.
+func (mcs *MixedContentSrv) ListAll(siteID uint) (findings []*MixedContent, err error) {
+	filters := MixedContentRequestFilters{PageNumber: 1}
+	for {
+		mr, err := mcs.List(siteID, filters)
+		if err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, mr.Data...)
+		if !mr.Meta.HasNextPage() {
+			return findings, nil
+		}
+
+		filters.PageNumber++
+	}
+}
+
+// GroupAcrossSites retrieves the mixed content of every https site
+// returned by SitesSrv.ListAll, walking all the pages of sites matching
+// the filters, and groups the findings by element name and origin.
+func (mcs *MixedContentSrv) GroupAcrossSites(filters ListSitesRequestFilters) (groups []*MixedContentGroup, err error) {
+	sites, err := mcs.client.Sites.ListAll(context.Background(), filters)
+	if err != nil {
+		return
+	}
+
+	findings := make(map[uint][]*MixedContent)
+	for _, s := range sites {
+		if !s.UsesHTTPS {
+			continue
+		}
+
+		findings[s.ID], err = mcs.ListAll(s.ID)
+		if err != nil {
+			return
+		}
+	}
+
+	groups = GroupMixedContent(findings)
+
+	return
+}
+
+// GroupMixedContent groups mixed content findings, indexed by site id,
+// by element name and origin.
+//
+// Groups affecting more sites come first, ties are sorted by
+// element name and origin.
+func GroupMixedContent(findings map[uint][]*MixedContent) (groups []*MixedContentGroup) {
+	type key struct{ element, origin string }
+
+	ids := make([]uint, 0, len(findings))
+	for id := range findings {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	idx := make(map[key]*MixedContentGroup)
+	for _, id := range ids {
+		for _, f := range findings[id] {
+			k := key{f.ElementName, f.Origin()}
+			g, ok := idx[k]
+			if !ok {
+				g = &MixedContentGroup{ElementName: k.element, Origin: k.origin}
+				idx[k] = g
+				groups = append(groups, g)
+			}
+
+			if n := len(g.SiteIDs); n == 0 || g.SiteIDs[n-1] != id {
+				g.SiteIDs = append(g.SiteIDs, id)
+			}
+			g.Findings = append(g.Findings, f)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].SiteIDs) != len(groups[j].SiteIDs) {
+			return len(groups[i].SiteIDs) > len(groups[j].SiteIDs)
+		}
+		if groups[i].ElementName != groups[j].ElementName {
+			return groups[i].ElementName < groups[j].ElementName
+		}
+
+		return groups[i].Origin < groups[j].Origin
+	})
+
+	return
+}
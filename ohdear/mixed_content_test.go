@@ -0,0 +1,92 @@
+package ohdear
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestMixedContentSrv_List(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/mixed-content/2", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.MixedContentResponse)
+	})
+
+	got, err := tClient.MixedContent.List(2, MixedContentRequestFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 3)
+	assert.Equal(t, "img", got.Data[0].ElementName)
+	assert.Equal(t, "http://cdn.tld/logo.png", got.Data[0].MixedContentURL)
+	assert.Equal(t, "http://cdn.tld", got.Data[0].Origin())
+}
+
+func TestMixedContentSrv_GroupAcrossSites(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[number]") == "2" {
+			_, _ = fmt.Fprint(w, testdata.SitesLastPageResponse)
+			return
+		}
+		_, _ = fmt.Fprint(w, testdata.SitesFirstPageResponse)
+	})
+	tMux.HandleFunc("/mixed-content/1", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("sites without https should be skipped")
+	})
+	tMux.HandleFunc("/mixed-content/2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testdata.MixedContentResponse)
+	})
+	tMux.HandleFunc("/mixed-content/3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testdata.MixedContentResponse)
+	})
+
+	got, err := tClient.MixedContent.GroupAcrossSites(ListSitesRequestFilters{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "img", got[0].ElementName)
+	assert.Equal(t, "http://cdn.tld", got[0].Origin)
+	assert.Equal(t, []uint{2, 3}, got[0].SiteIDs)
+	assert.Len(t, got[0].Findings, 4)
+}
+
+func TestGroupMixedContent(t *testing.T) {
+	logo := &MixedContent{ElementName: "img", MixedContentURL: "http://cdn.tld/logo.png"}
+	tracker := &MixedContent{ElementName: "script", MixedContentURL: "http://tracker.tld/t.js"}
+
+	got := GroupMixedContent(map[uint][]*MixedContent{
+		1: {tracker},
+		2: {logo, tracker},
+		3: {},
+	})
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "http://tracker.tld", got[0].Origin)
+	assert.Equal(t, []uint{1, 2}, got[0].SiteIDs)
+	assert.Equal(t, "img", got[1].ElementName)
+	assert.Equal(t, []uint{2}, got[1].SiteIDs)
+}
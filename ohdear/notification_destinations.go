@@ -0,0 +1,476 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Notification destinations resource paths.
+const (
+	TeamNotificationDestinationsBasePath string = "team-notification-destinations"
+	SiteNotificationDestinationsPath     string = "notification-destinations"
+)
+
+// NotificationDestinationsSrv operates over the team and site
+// level notification destinations.
+type NotificationDestinationsSrv srv
+
+// NotificationChannel is the medium used to deliver the notifications.
+type NotificationChannel string
+
+// Supported NotificationChannel values.
+const (
+	ChannelMail           NotificationChannel = "mail"
+	ChannelSlack          NotificationChannel = "slack"
+	ChannelWebhook        NotificationChannel = "webhook"
+	ChannelSMS            NotificationChannel = "sms"
+	ChannelDiscord        NotificationChannel = "discord"
+	ChannelTelegram       NotificationChannel = "telegram"
+	ChannelPushover       NotificationChannel = "pushover"
+	ChannelMicrosoftTeams NotificationChannel = "msteams"
+)
+
+// NotifiableEvent is an event a notification destination can subscribe to.
+type NotifiableEvent string
+
+// Supported NotifiableEvent values.
+const (
+	UptimeCheckFailedNotification       NotifiableEvent = "UptimeCheckFailedNotification"
+	UptimeCheckRecoveredNotification    NotifiableEvent = "UptimeCheckRecoveredNotification"
+	BrokenLinksFoundNotification        NotifiableEvent = "BrokenLinksFoundNotification"
+	BrokenLinksFixedNotification        NotifiableEvent = "BrokenLinksFixedNotification"
+	MixedContentFoundNotification       NotifiableEvent = "MixedContentFoundNotification"
+	MixedContentFixedNotification       NotifiableEvent = "MixedContentFixedNotification"
+	CertificateExpiresSoonNotification  NotifiableEvent = "CertificateExpiresSoonNotification"
+	CertificateHasChangedNotification   NotifiableEvent = "CertificateHasChangedNotification"
+	CertificateUnhealthyNotification    NotifiableEvent = "CertificateUnhealthyNotification"
+	CertificateFixedNotification        NotifiableEvent = "CertificateFixedNotification"
+	ApplicationHealthFailedNotification NotifiableEvent = "ApplicationHealthCheckFailedNotification"
+	ApplicationHealthFixedNotification  NotifiableEvent = "ApplicationHealthCheckRecoveredNotification"
+)
+
+// NotifiableEvents lists every supported NotifiableEvent.
+var NotifiableEvents = []NotifiableEvent{
+	UptimeCheckFailedNotification,
+	UptimeCheckRecoveredNotification,
+	BrokenLinksFoundNotification,
+	BrokenLinksFixedNotification,
+	MixedContentFoundNotification,
+	MixedContentFixedNotification,
+	CertificateExpiresSoonNotification,
+	CertificateHasChangedNotification,
+	CertificateUnhealthyNotification,
+	CertificateFixedNotification,
+	ApplicationHealthFailedNotification,
+	ApplicationHealthFixedNotification,
+}
+
+// NotificationDestination represents a configured notification destination,
+// the channel settings are available through Settings.
+type NotificationDestination struct {
+	ID                uint                `json:"id,omitempty"`
+	Channel           NotificationChannel `json:"channel,omitempty"`
+	Destination       json.RawMessage     `json:"destination,omitempty"`
+	NotificationTypes []NotifiableEvent   `json:"notification_types,omitempty"`
+	CreatedAt         *CustomDate         `json:"created_at,omitempty"`
+	UpdatedAt         *CustomDate         `json:"updated_at,omitempty"`
+}
+
+// NotificationDestinationsResponse holds the notification destinations
+// inside an outer data wrapper.
+type NotificationDestinationsResponse struct {
+	Data []*NotificationDestination `json:"data"`
+}
+
+// Subscribes reports whether the destination is notified about the event.
+func (nd *NotificationDestination) Subscribes(e NotifiableEvent) bool {
+	for _, t := range nd.NotificationTypes {
+		if t == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Settings decodes the destination into the typed settings of its channel.
+func (nd *NotificationDestination) Settings() (cs ChannelSettings, err error) {
+	switch nd.Channel {
+	case ChannelMail:
+		cs = &MailSettings{}
+	case ChannelSlack:
+		cs = &SlackSettings{}
+	case ChannelWebhook:
+		cs = &WebhookSettings{}
+	case ChannelSMS:
+		cs = &SMSSettings{}
+	case ChannelDiscord:
+		cs = &DiscordSettings{}
+	case ChannelTelegram:
+		cs = &TelegramSettings{}
+	case ChannelPushover:
+		cs = &PushoverSettings{}
+	case ChannelMicrosoftTeams:
+		cs = &MicrosoftTeamsSettings{}
+	default:
+		return nil, fmt.Errorf("%w: unsupported channel %q", ErrInvalidNotificationDestination, nd.Channel)
+	}
+
+	if len(nd.Destination) == 0 {
+		return
+	}
+
+	if err = json.Unmarshal(nd.Destination, cs); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// ChannelSettings are the channel specific settings of a destination.
+type ChannelSettings interface {
+	Channel() NotificationChannel
+	Validate() error
+}
+
+// MailSettings delivers the notifications to an email address.
+type MailSettings struct {
+	Mail string `json:"mail"`
+}
+
+// Channel implements ChannelSettings.
+func (s *MailSettings) Channel() NotificationChannel { return ChannelMail }
+
+// Validate checks the email address.
+func (s *MailSettings) Validate() error {
+	if _, err := mail.ParseAddress(s.Mail); err != nil {
+		return fmt.Errorf("%w: invalid email address %q", ErrInvalidNotificationDestination, s.Mail)
+	}
+
+	return nil
+}
+
+// SlackSettings posts the notifications to a Slack incoming webhook.
+type SlackSettings struct {
+	URL string `json:"url"`
+	// SlackChannel overrides the channel configured in the webhook.
+	SlackChannel string `json:"channel,omitempty"`
+}
+
+// Channel implements ChannelSettings.
+func (s *SlackSettings) Channel() NotificationChannel { return ChannelSlack }
+
+// Validate checks the webhook url.
+func (s *SlackSettings) Validate() error {
+	return validateDestinationURL(s.URL)
+}
+
+// WebhookSettings posts the notifications to a generic webhook,
+// the secret is used to sign the calls.
+type WebhookSettings struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// Channel implements ChannelSettings.
+func (s *WebhookSettings) Channel() NotificationChannel { return ChannelWebhook }
+
+// Validate checks the webhook url.
+func (s *WebhookSettings) Validate() error {
+	return validateDestinationURL(s.URL)
+}
+
+// SMSSettings sends the notifications as text messages, the phone
+// number uses the E.164 format.
+type SMSSettings struct {
+	PhoneNumber string `json:"phone_number"`
+}
+
+// Channel implements ChannelSettings.
+func (s *SMSSettings) Channel() NotificationChannel { return ChannelSMS }
+
+// Validate checks the phone number is a + followed by 8 to 15 digits.
+func (s *SMSSettings) Validate() error {
+	digits := strings.TrimPrefix(s.PhoneNumber, "+")
+	if digits == s.PhoneNumber || len(digits) < 8 || len(digits) > 15 || strings.Trim(digits, "0123456789") != "" {
+		return fmt.Errorf("%w: invalid phone number %q", ErrInvalidNotificationDestination, s.PhoneNumber)
+	}
+
+	return nil
+}
+
+// DiscordSettings posts the notifications to a Discord webhook.
+type DiscordSettings struct {
+	URL string `json:"url"`
+}
+
+// Channel implements ChannelSettings.
+func (s *DiscordSettings) Channel() NotificationChannel { return ChannelDiscord }
+
+// Validate checks the webhook url.
+func (s *DiscordSettings) Validate() error {
+	return validateDestinationURL(s.URL)
+}
+
+// TelegramSettings sends the notifications to a Telegram chat.
+type TelegramSettings struct {
+	ChatID string `json:"chat_id"`
+}
+
+// Channel implements ChannelSettings.
+func (s *TelegramSettings) Channel() NotificationChannel { return ChannelTelegram }
+
+// Validate checks the chat id is present.
+func (s *TelegramSettings) Validate() error {
+	if strings.TrimSpace(s.ChatID) == "" {
+		return fmt.Errorf("%w: missing telegram chat id", ErrInvalidNotificationDestination)
+	}
+
+	return nil
+}
+
+// PushoverSettings sends the notifications through Pushover,
+// the priority ranges from -2 to 2.
+type PushoverSettings struct {
+	UserKey  string `json:"user_key"`
+	Device   string `json:"device,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// Channel implements ChannelSettings.
+func (s *PushoverSettings) Channel() NotificationChannel { return ChannelPushover }
+
+// Validate checks the user key and the priority.
+func (s *PushoverSettings) Validate() error {
+	if strings.TrimSpace(s.UserKey) == "" {
+		return fmt.Errorf("%w: missing pushover user key", ErrInvalidNotificationDestination)
+	}
+
+	if s.Priority < -2 || s.Priority > 2 {
+		return fmt.Errorf("%w: invalid pushover priority %d", ErrInvalidNotificationDestination, s.Priority)
+	}
+
+	return nil
+}
+
+// MicrosoftTeamsSettings posts the notifications to a Microsoft Teams webhook.
+type MicrosoftTeamsSettings struct {
+	URL string `json:"url"`
+}
+
+// Channel implements ChannelSettings.
+func (s *MicrosoftTeamsSettings) Channel() NotificationChannel { return ChannelMicrosoftTeams }
+
+// Validate checks the webhook url.
+func (s *MicrosoftTeamsSettings) Validate() error {
+	return validateDestinationURL(s.URL)
+}
+
+func validateDestinationURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: invalid url %q", ErrInvalidNotificationDestination, raw)
+	}
+
+	return nil
+}
+
+// NotificationDestinationRequest describes the request body required to
+// create or update a notification destination.
+type NotificationDestinationRequest struct {
+	Channel           NotificationChannel `json:"channel"`
+	Destination       ChannelSettings     `json:"destination"`
+	NotificationTypes []NotifiableEvent   `json:"notification_types"`
+}
+
+// NewNotificationDestinationRequest builds a request for the channel
+// of the settings subscribed to the given events.
+func NewNotificationDestinationRequest(settings ChannelSettings, events ...NotifiableEvent) *NotificationDestinationRequest {
+	nr := &NotificationDestinationRequest{Destination: settings, NotificationTypes: events}
+	if settings != nil {
+		nr.Channel = settings.Channel()
+	}
+
+	return nr
+}
+
+// Validate checks the channel settings and the subscribed events.
+func (nr *NotificationDestinationRequest) Validate() error {
+	if isNilSettings(nr.Destination) {
+		return fmt.Errorf("%w: missing destination settings", ErrInvalidNotificationDestination)
+	}
+
+	if nr.Channel != nr.Destination.Channel() {
+		return fmt.Errorf("%w: channel %q does not match %q settings", ErrInvalidNotificationDestination, nr.Channel, nr.Destination.Channel())
+	}
+
+	if err := nr.Destination.Validate(); err != nil {
+		return err
+	}
+
+	if len(nr.NotificationTypes) == 0 {
+		return fmt.Errorf("%w: at least one notifiable event is required", ErrInvalidNotificationDestination)
+	}
+
+	for _, e := range nr.NotificationTypes {
+		if !knownNotifiableEvent(e) {
+			return fmt.Errorf("%w: unknown notifiable event %q", ErrInvalidNotificationDestination, e)
+		}
+	}
+
+	return nil
+}
+
+// isNilSettings reports whether the settings are nil, including
+// typed nil pointers such as (*MailSettings)(nil).
+func isNilSettings(cs ChannelSettings) bool {
+	if cs == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(cs)
+
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func knownNotifiableEvent(e NotifiableEvent) bool {
+	for _, k := range NotifiableEvents {
+		if k == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotificationScope selects the team or the site owning
+// the notification destinations.
+type NotificationScope struct {
+	TeamID uint
+	SiteID uint
+}
+
+// TeamScope selects the destinations of a team.
+func TeamScope(id uint) NotificationScope {
+	return NotificationScope{TeamID: id}
+}
+
+// SiteScope selects the destinations of a site.
+func SiteScope(id uint) NotificationScope {
+	return NotificationScope{SiteID: id}
+}
+
+func (ns NotificationScope) path() (string, error) {
+	switch {
+	case ns.TeamID != 0 && ns.SiteID == 0:
+		return fmt.Sprintf("%s/%d", TeamNotificationDestinationsBasePath, ns.TeamID), nil
+	case ns.SiteID != 0 && ns.TeamID == 0:
+		return fmt.Sprintf("%s/%d/%s", SitesBasePath, ns.SiteID, SiteNotificationDestinationsPath), nil
+	}
+
+	return "", fmt.Errorf("%w: the scope requires either a team or a site", ErrInvalidNotificationDestination)
+}
+
+// List returns the notification destinations of a team or a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#notification-destinations
+func (nds *NotificationDestinationsSrv) List(scope NotificationScope) (nd []*NotificationDestination, err error) {
+	p, err := scope.path()
+	if err != nil {
+		return
+	}
+
+	req, err := nds.client.NewAPIRequest(http.MethodGet, p, nil)
+	if err != nil {
+		return
+	}
+
+	res, err := nds.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	var nr NotificationDestinationsResponse
+	if err = json.Unmarshal(res.content, &nr); err != nil {
+		return
+	}
+
+	return nr.Data, nil
+}
+
+// Create adds a notification destination to a team or a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#notification-destinations
+func (nds *NotificationDestinationsSrv) Create(scope NotificationScope, body *NotificationDestinationRequest) (nd *NotificationDestination, err error) {
+	p, err := scope.path()
+	if err != nil {
+		return
+	}
+
+	return nds.save(http.MethodPost, p, body)
+}
+
+// Update replaces the settings and events of a notification destination.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#notification-destinations
+func (nds *NotificationDestinationsSrv) Update(scope NotificationScope, id uint, body *NotificationDestinationRequest) (nd *NotificationDestination, err error) {
+	p, err := scope.path()
+	if err != nil {
+		return
+	}
+
+	return nds.save(http.MethodPut, fmt.Sprintf("%s/%d", p, id), body)
+}
+
+// Delete removes a notification destination.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#notification-destinations
+func (nds *NotificationDestinationsSrv) Delete(scope NotificationScope, id uint) (err error) {
+	p, err := scope.path()
+	if err != nil {
+		return
+	}
+
+	req, err := nds.client.NewAPIRequest(http.MethodDelete, fmt.Sprintf("%s/%d", p, id), nil)
+	if err != nil {
+		return
+	}
+
+	_, err = nds.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (nds *NotificationDestinationsSrv) save(method, uri string, body *NotificationDestinationRequest) (nd *NotificationDestination, err error) {
+	if body == nil {
+		return nil, fmt.Errorf("%w: missing request body", ErrInvalidNotificationDestination)
+	}
+
+	if err = body.Validate(); err != nil {
+		return
+	}
+
+	req, err := nds.client.NewAPIRequest(method, uri, body)
+	if err != nil {
+		return
+	}
+
+	res, err := nds.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &nd); err != nil {
+		return
+	}
+
+	return
+}
@@ -0,0 +1,174 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestNotificationDestinationsSrv_List(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/team-notification-destinations/7", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.NotificationDestinationsResponse)
+	})
+
+	got, err := tClient.NotificationDestinations.List(TeamScope(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, ChannelMail, got[0].Channel)
+	assert.True(t, got[0].Subscribes(UptimeCheckRecoveredNotification))
+	assert.False(t, got[0].Subscribes(CertificateFixedNotification))
+
+	s, err := got[0].Settings()
+	assert.Nil(t, err)
+	assert.Equal(t, &MailSettings{Mail: "ops@example.com"}, s)
+
+	s, err = got[1].Settings()
+	assert.Nil(t, err)
+	assert.Equal(t, &PushoverSettings{UserKey: "uQiRzpo4DXghDmr9QzzfQu27cmVRsG", Priority: 1}, s)
+}
+
+func TestNotificationDestinationsSrv_Create(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/notification-destinations", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		b, _ := ioutil.ReadAll(r.Body)
+		var body map[string]interface{}
+		_ = json.Unmarshal(b, &body)
+		assert.Equal(t, "slack", body["channel"])
+		assert.Equal(t, map[string]interface{}{
+			"url":     "https://hooks.slack.com/services/T000/B000/XXXX",
+			"channel": "#alerts",
+		}, body["destination"])
+		assert.Equal(t, []interface{}{"UptimeCheckFailedNotification"}, body["notification_types"])
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, testdata.NotificationDestinationResponse)
+	})
+
+	req := NewNotificationDestinationRequest(
+		&SlackSettings{URL: "https://hooks.slack.com/services/T000/B000/XXXX", SlackChannel: "#alerts"},
+		UptimeCheckFailedNotification,
+	)
+
+	got, err := tClient.NotificationDestinations.Create(SiteScope(1), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint(3), got.ID)
+	assert.Equal(t, ChannelSlack, got.Channel)
+}
+
+func TestNotificationDestinationsSrv_Update(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/team-notification-destinations/7/3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.NotificationDestinationResponse)
+	})
+
+	req := NewNotificationDestinationRequest(
+		&SlackSettings{URL: "https://hooks.slack.com/services/T000/B000/XXXX"},
+		UptimeCheckFailedNotification,
+	)
+
+	got, err := tClient.NotificationDestinations.Update(TeamScope(7), 3, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint(3), got.ID)
+}
+
+func TestNotificationDestinationsSrv_Delete(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/notification-destinations/3", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	assert.Nil(t, tClient.NotificationDestinations.Delete(SiteScope(1), 3))
+
+	err := tClient.NotificationDestinations.Delete(NotificationScope{TeamID: 1, SiteID: 1}, 3)
+	assert.True(t, errors.Is(err, ErrInvalidNotificationDestination))
+}
+
+func TestNotificationDestinationRequest_Validate(t *testing.T) {
+	cases := []struct {
+		name  string
+		req   *NotificationDestinationRequest
+		valid bool
+	}{
+		{"mail", NewNotificationDestinationRequest(&MailSettings{Mail: "ops@example.com"}, UptimeCheckFailedNotification), true},
+		{"invalid mail", NewNotificationDestinationRequest(&MailSettings{Mail: "ops"}, UptimeCheckFailedNotification), false},
+		{"webhook", NewNotificationDestinationRequest(&WebhookSettings{URL: "https://example.com/hook"}, BrokenLinksFoundNotification), true},
+		{"relative url", NewNotificationDestinationRequest(&DiscordSettings{URL: "/hook"}, BrokenLinksFoundNotification), false},
+		{"sms", NewNotificationDestinationRequest(&SMSSettings{PhoneNumber: "+32470123456"}, UptimeCheckFailedNotification), true},
+		{"sms without prefix", NewNotificationDestinationRequest(&SMSSettings{PhoneNumber: "32470123456"}, UptimeCheckFailedNotification), false},
+		{"telegram", NewNotificationDestinationRequest(&TelegramSettings{}, UptimeCheckFailedNotification), false},
+		{"pushover priority", NewNotificationDestinationRequest(&PushoverSettings{UserKey: "key", Priority: 3}, UptimeCheckFailedNotification), false},
+		{"no events", NewNotificationDestinationRequest(&MicrosoftTeamsSettings{URL: "https://example.com/hook"}), false},
+		{"unknown event", NewNotificationDestinationRequest(&MicrosoftTeamsSettings{URL: "https://example.com/hook"}, "SomethingNotification"), false},
+		{"no settings", NewNotificationDestinationRequest(nil, UptimeCheckFailedNotification), false},
+		{"typed nil settings", NewNotificationDestinationRequest((*MailSettings)(nil), UptimeCheckFailedNotification), false},
+		{"channel mismatch", &NotificationDestinationRequest{Channel: ChannelMail, Destination: &SlackSettings{URL: "https://example.com"}, NotificationTypes: []NotifiableEvent{UptimeCheckFailedNotification}}, false},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			err := c.req.Validate()
+			if c.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidNotificationDestination), "%v", err)
+			}
+		})
+	}
+}
+
+func TestNotificationDestination_SettingsUnknownChannel(t *testing.T) {
+	nd := &NotificationDestination{Channel: "carrier-pigeon"}
+
+	_, err := nd.Settings()
+	assert.True(t, errors.Is(err, ErrInvalidNotificationDestination))
+}
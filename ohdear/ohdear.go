@@ -2,6 +2,7 @@ package ohdear
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -14,12 +15,19 @@ const (
 	APITokenEnv         string = "OHDEAR_API_TOKEN"
 	ContentExchangeType string = "application/json"
 	AuthHeader          string = "Authorization"
+	FilterDateLayout    string = "20060102150405"
+	CustomDateLayout    string = "2006-01-02 15:04:05"
 )
 
 // Oh-dear package level errors
 var (
-	ErrEmptyAPIToken  error = fmt.Errorf("your api token is empty, please provide a non-empty token")
-	ErrInvalidBaseURL error = fmt.Errorf("your base url must contain a trailing slash")
+	ErrEmptyAPIToken                  error = fmt.Errorf("your api token is empty, please provide a non-empty token")
+	ErrInvalidBaseURL                 error = fmt.Errorf("your base url must contain a trailing slash")
+	ErrInvalidWhitelistURL            error = fmt.Errorf("whitelisted urls must be absolute http or https urls")
+	ErrInvalidSiteSettings            error = fmt.Errorf("invalid site settings")
+	ErrInvalidNotificationDestination error = fmt.Errorf("invalid notification destination")
+	ErrInvalidSitemapPath             error = fmt.Errorf("sitemap paths must be absolute paths without scheme nor host")
+	ErrTeamNotFound                   error = fmt.Errorf("no single team matches the given name")
 )
 
 // CheckResponse checks the API response for errors, and returns them if
@@ -56,6 +64,48 @@ func newError(r *http.Response) *Error {
 	return &e
 }
 
+// PaginationLinks contains the navigation links returned
+// alongside paginated collections.
+type PaginationLinks struct {
+	First string `json:"first,omitempty"`
+	Last  string `json:"last,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+}
+
+// PaginationMeta describes the position of a page inside
+// a paginated collection.
+type PaginationMeta struct {
+	CurrentPage uint   `json:"current_page,omitempty"`
+	From        uint   `json:"from,omitempty"`
+	LastPage    uint   `json:"last_page,omitempty"`
+	Path        string `json:"path,omitempty"`
+	PerPage     uint   `json:"per_page,omitempty"`
+	To          uint   `json:"to,omitempty"`
+	Total       uint   `json:"total,omitempty"`
+}
+
+// HasNextPage reports if there are more pages after the current one.
+func (pm *PaginationMeta) HasNextPage() bool {
+	if pm == nil {
+		return false
+	}
+
+	return pm.CurrentPage < pm.LastPage
+}
+
+// FilterDate formats a time using the layout expected
+// by the date filters of the API.
+func FilterDate(t time.Time) string {
+	return t.UTC().Format(FilterDateLayout)
+}
+
+// daysUntil returns the amount of whole days left before t, rounded
+// down so anything already past reports a negative value.
+func daysUntil(t time.Time) int {
+	return int(math.Floor(time.Until(t).Hours() / 24))
+}
+
 type CustomDate struct {
 	time.Time
 }
@@ -63,10 +113,14 @@ type CustomDate struct {
 func (d *CustomDate) UnmarshalJSON(b []byte) error {
 	s := string(b)
 	s = strings.Trim(s, "\"")
-	t, err := time.Parse("2006-01-02 15:04:05", s)
+	t, err := time.Parse(CustomDateLayout, s)
 	if err != nil {
 		return err
 	}
 	d.Time = t
 	return nil
 }
+
+func (d CustomDate) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.Format(CustomDateLayout) + `"`), nil
+}
@@ -0,0 +1,239 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+// PerformanceRecordsPath is the resource path appended to a site.
+const PerformanceRecordsPath string = "performance-records"
+
+// PerformanceSrv operates over the response time measurements of a site.
+type PerformanceSrv srv
+
+// PerformanceRecord describes a single response time measurement,
+// all the timings are expressed in seconds and MeasuredFrom names
+// the location of the probe.
+type PerformanceRecord struct {
+	ID               uint        `json:"id,omitempty"`
+	SiteID           uint        `json:"site_id,omitempty"`
+	DNSTime          float64     `json:"dns_time_in_seconds"`
+	TCPTime          float64     `json:"tcp_time_in_seconds"`
+	TLSHandshakeTime float64     `json:"ssl_handshake_time_in_seconds"`
+	RemoteServerTime float64     `json:"remote_server_processing_time_in_seconds"`
+	DownloadTime     float64     `json:"download_time_in_seconds"`
+	TotalTime        float64     `json:"total_time_in_seconds"`
+	MeasuredFrom     string      `json:"measured_from,omitempty"`
+	CreatedAt        *CustomDate `json:"created_at,omitempty"`
+}
+
+// PerformanceRecordsResponse is a page of performance records
+// inside an outer data wrapper.
+type PerformanceRecordsResponse struct {
+	Data  []*PerformanceRecord `json:"data"`
+	Links *PaginationLinks     `json:"links,omitempty"`
+	Meta  *PaginationMeta      `json:"meta,omitempty"`
+}
+
+// PerformanceRequestFilters adds the time window and the pagination
+// parameters to performance requests.
+// The specified dates should be represented as follows:
+// 20200801000000.
+//
+// The window is required, the pagination values are not.
+type PerformanceRequestFilters struct {
+	StartedAt  string `url:"filter[start]"`
+	EndedAt    string `url:"filter[end]"`
+	PageSize   uint   `url:"page[size],omitempty"`
+	PageNumber uint   `url:"page[number],omitempty"`
+}
+
+// List returns a page of the performance records of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#performance
+func (ps *PerformanceSrv) List(siteID uint, filters PerformanceRequestFilters) (pr *PerformanceRecordsResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := ps.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/%s?%s", SitesBasePath, siteID, PerformanceRecordsPath, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ps.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &pr); err != nil {
+		return
+	}
+
+	return
+}
+
+// Window walks every page of performance records measured
+// for a site between start and end.
+func (ps *PerformanceSrv) Window(siteID uint, start, end time.Time) (records []*PerformanceRecord, err error) {
+	filters := PerformanceRequestFilters{
+		StartedAt:  FilterDate(start),
+		EndedAt:    FilterDate(end),
+		PageNumber: 1,
+	}
+
+	for {
+		pr, err := ps.List(siteID, filters)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, pr.Data...)
+		if !pr.Meta.HasNextPage() {
+			return records, nil
+		}
+
+		filters.PageNumber++
+	}
+}
+
+// PerformanceMetric selects one of the timings of a performance record.
+type PerformanceMetric string
+
+// Supported PerformanceMetric values.
+const (
+	MetricDNSTime          PerformanceMetric = "dns"
+	MetricTCPTime          PerformanceMetric = "tcp"
+	MetricTLSHandshakeTime PerformanceMetric = "tls_handshake"
+	MetricRemoteServerTime PerformanceMetric = "remote_server"
+	MetricDownloadTime     PerformanceMetric = "download"
+	MetricTotalTime        PerformanceMetric = "total"
+)
+
+// Value returns the timing of the record selected by the metric,
+// unknown metrics select the total time.
+func (m PerformanceMetric) Value(r *PerformanceRecord) float64 {
+	switch m {
+	case MetricDNSTime:
+		return r.DNSTime
+	case MetricTCPTime:
+		return r.TCPTime
+	case MetricTLSHandshakeTime:
+		return r.TLSHandshakeTime
+	case MetricRemoteServerTime:
+		return r.RemoteServerTime
+	case MetricDownloadTime:
+		return r.DownloadTime
+	default:
+		return r.TotalTime
+	}
+}
+
+// PerformanceBucket summarizes the records measured between Start and End.
+type PerformanceBucket struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Count int       `json:"count"`
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+	Mean  float64   `json:"mean"`
+	P50   float64   `json:"p50"`
+	P95   float64   `json:"p95"`
+	P99   float64   `json:"p99"`
+}
+
+// AggregatePerformance groups the records by hour, day or month in UTC
+// and computes the percentiles of the metric for every bucket.
+//
+// Records without a creation date are ignored, the buckets are
+// returned in chronological order and only contain measured periods.
+func AggregatePerformance(records []*PerformanceRecord, metric PerformanceMetric, split SplitValue) (buckets []*PerformanceBucket) {
+	values := make(map[time.Time][]float64)
+	for _, r := range records {
+		if r == nil || r.CreatedAt == nil {
+			continue
+		}
+
+		start := splitStart(r.CreatedAt.Time, split)
+		values[start] = append(values[start], metric.Value(r))
+	}
+
+	for start, v := range values {
+		sort.Float64s(v)
+
+		var sum float64
+		for _, x := range v {
+			sum += x
+		}
+
+		buckets = append(buckets, &PerformanceBucket{
+			Start: start,
+			End:   splitEnd(start, split),
+			Count: len(v),
+			Min:   v[0],
+			Max:   v[len(v)-1],
+			Mean:  sum / float64(len(v)),
+			P50:   Percentile(v, 50),
+			P95:   Percentile(v, 95),
+			P99:   Percentile(v, 99),
+		})
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Start.Before(buckets[j].Start)
+	})
+
+	return
+}
+
+// Percentile returns the nearest-rank percentile p, between 0 and 100,
+// of the sorted values. Zero is returned for empty values.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
+
+// splitStart truncates t to the beginning of its hour, day or month in UTC,
+// unknown split values truncate to the hour.
+func splitStart(t time.Time, split SplitValue) time.Time {
+	t = t.UTC()
+	switch split {
+	case SplitByDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case SplitByMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return t.Truncate(time.Hour)
+	}
+}
+
+// splitEnd returns the end of the bucket starting at t.
+func splitEnd(t time.Time, split SplitValue) time.Time {
+	switch split {
+	case SplitByDay:
+		return t.AddDate(0, 0, 1)
+	case SplitByMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.Add(time.Hour)
+	}
+}
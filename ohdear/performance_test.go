@@ -0,0 +1,111 @@
+package ohdear
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestPerformanceSrv_Window(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/performance-records", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "20200801000000", r.URL.Query().Get("filter[start]"))
+		assert.Equal(t, "20200802000000", r.URL.Query().Get("filter[end]"))
+
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("page[number]") == "2" {
+			_, _ = fmt.Fprint(w, testdata.PerformanceRecordsPage2Response)
+			return
+		}
+		_, _ = fmt.Fprint(w, testdata.PerformanceRecordsPage1Response)
+	})
+
+	start := time.Date(2020, time.August, 1, 0, 0, 0, 0, time.UTC)
+	got, err := tClient.Performance.Window(1, start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 3)
+	assert.Equal(t, 0.034, got[0].TLSHandshakeTime)
+	assert.Equal(t, "New York", got[2].MeasuredFrom)
+
+	hourly := AggregatePerformance(got, MetricTotalTime, SplitByHour)
+	assert.Len(t, hourly, 2)
+	assert.Equal(t, 2, hourly[0].Count)
+	assert.Equal(t, start.Add(10*time.Hour), hourly[0].Start)
+	assert.Equal(t, start.Add(11*time.Hour), hourly[0].End)
+	assert.Equal(t, 0.17, hourly[0].P50)
+	assert.Equal(t, 0.46, hourly[0].P99)
+
+	daily := AggregatePerformance(got, MetricRemoteServerTime, SplitByDay)
+	assert.Len(t, daily, 1)
+	assert.Equal(t, 3, daily[0].Count)
+	assert.Equal(t, 0.12, daily[0].P50)
+	assert.Equal(t, 0.41, daily[0].P95)
+	assert.Equal(t, 0.1, daily[0].Min)
+}
+
+func TestAggregatePerformance(t *testing.T) {
+	at := func(d string) *CustomDate {
+		v, _ := time.Parse(CustomDateLayout, d)
+		return &CustomDate{v}
+	}
+
+	var records []*PerformanceRecord
+	for i := 1; i <= 100; i++ {
+		records = append(records, &PerformanceRecord{
+			DNSTime:   float64(i),
+			CreatedAt: at(fmt.Sprintf("2020-0%d-15 12:00:00", 7+i%2)),
+		})
+	}
+	records = append(records, &PerformanceRecord{DNSTime: 1000}, nil)
+
+	got := AggregatePerformance(records, MetricDNSTime, SplitByMonth)
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, time.July, got[0].Start.Month())
+	assert.Equal(t, time.August, got[0].End.Month())
+	assert.Equal(t, 50, got[0].Count)
+	assert.Equal(t, 50.0, got[0].P50)
+	assert.Equal(t, 96.0, got[0].P95)
+	assert.Equal(t, 100.0, got[0].P99)
+	assert.Equal(t, 51.0, got[0].Mean)
+	assert.Equal(t, 99.0, got[1].Max)
+	assert.Nil(t, AggregatePerformance(nil, MetricTotalTime, SplitByDay))
+}
+
+func TestPercentile(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{"empty", nil, 50, 0},
+		{"single", []float64{3}, 99, 3},
+		{"median", []float64{1, 2, 3, 4}, 50, 2},
+		{"zero", []float64{1, 2, 3, 4}, 0, 1},
+		{"max", []float64{1, 2, 3, 4}, 100, 4},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, Percentile(c.values, c.p))
+		})
+	}
+}
@@ -0,0 +1,495 @@
+// Package render writes oh-dear resources in human and machine
+// readable formats.
+//
+// Any struct or slice of structs can be rendered, the columns are the
+// JSON names of the struct fields in declaration order, so the column
+// names match the ones returned by the API, e.g. summarized_check_result.
+//
+// Supported formats are aligned tables, JSON, NDJSON, YAML, CSV and
+// text/template custom formats executed once per item.
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies an output format.
+type Format string
+
+// Supported Format values.
+const (
+	Table    Format = "table"
+	JSON     Format = "json"
+	NDJSON   Format = "ndjson"
+	YAML     Format = "yaml"
+	CSV      Format = "csv"
+	Template Format = "template"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{Table, JSON, NDJSON, YAML, CSV, Template}
+
+// Package level errors
+var (
+	ErrUnknownFormat   error = fmt.Errorf("unknown output format")
+	ErrUnknownColumn   error = fmt.Errorf("unknown column")
+	ErrEmptyTemplate   error = fmt.Errorf("the template format requires a template")
+	ErrInvalidTemplate error = fmt.Errorf("invalid template")
+	ErrUnsupportedKind error = fmt.Errorf("only structs and slices of structs can be rendered")
+)
+
+// Options controls how items are rendered.
+type Options struct {
+	Format Format
+	// Columns restricts and orders the rendered columns,
+	// all the columns are rendered when empty.
+	Columns []string
+	// SortBy is the column used to order items, prefix it
+	// with a `-` sign to sort in descending order.
+	SortBy string
+	// Template is the text/template used by the Template format,
+	// it is executed for every item with its columns as fields.
+	Template string
+}
+
+// ParseFormat validates a format name.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+}
+
+// Render writes the items, a struct or a slice of structs, using the
+// given options.
+//
+// A single struct is rendered as an object by the JSON and YAML formats
+// and as a vertical list of columns and values by the Table format.
+func Render(w io.Writer, items interface{}, opts Options) error {
+	set, err := newRecordSet(items)
+	if err != nil {
+		return err
+	}
+
+	if err := set.selectColumns(opts.Columns); err != nil {
+		return err
+	}
+
+	if err := set.sort(opts.SortBy); err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case Table, "":
+		if set.single {
+			return set.writeDetails(w)
+		}
+		return set.writeTable(w)
+	case JSON:
+		return set.writeJSON(w)
+	case NDJSON:
+		return set.writeNDJSON(w)
+	case YAML:
+		return set.writeYAML(w)
+	case CSV:
+		return set.writeCSV(w)
+	case Template:
+		return set.writeTemplate(w, opts.Template)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, opts.Format)
+}
+
+// record is an ordered view of an item.
+type record struct {
+	columns []string
+	values  map[string]interface{}
+}
+
+// recordSet holds the records built from the rendered items.
+type recordSet struct {
+	all     []string
+	columns []string
+	records []*record
+	single  bool
+}
+
+func newRecordSet(items interface{}) (*recordSet, error) {
+	v := reflect.ValueOf(items)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return &recordSet{}, nil
+		}
+		v = v.Elem()
+	}
+
+	set := new(recordSet)
+	var elem reflect.Type
+	switch v.Kind() {
+	case reflect.Struct:
+		set.single = true
+		elem = v.Type()
+		s := reflect.New(elem)
+		s.Elem().Set(v)
+		v = reflect.Append(reflect.MakeSlice(reflect.SliceOf(s.Type()), 0, 1), s)
+	case reflect.Slice, reflect.Array:
+		elem = v.Type().Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+	default:
+		return nil, ErrUnsupportedKind
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedKind
+	}
+
+	set.all = columnsOf(elem)
+	set.columns = set.all
+	for i := 0; i < v.Len(); i++ {
+		r, err := newRecord(v.Index(i).Interface(), set.columns)
+		if err != nil {
+			return nil, err
+		}
+		set.records = append(set.records, r)
+	}
+
+	return set, nil
+}
+
+func newRecord(item interface{}, columns []string) (*record, error) {
+	b, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &record{columns: columns, values: make(map[string]interface{})}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&r.values); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// columnsOf returns the JSON names of the exported fields of a struct.
+func columnsOf(t reflect.Type) (columns []string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+
+		columns = append(columns, name)
+	}
+
+	return
+}
+
+func (rs *recordSet) selectColumns(columns []string) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	for _, c := range columns {
+		if !contains(rs.all, c) {
+			return fmt.Errorf("%w: %q", ErrUnknownColumn, c)
+		}
+	}
+
+	rs.columns = columns
+	for _, r := range rs.records {
+		r.columns = columns
+	}
+
+	return nil
+}
+
+func (rs *recordSet) sort(by string) error {
+	if by == "" {
+		return nil
+	}
+
+	desc := strings.HasPrefix(by, "-")
+	by = strings.TrimPrefix(by, "-")
+
+	if !contains(rs.all, by) {
+		return fmt.Errorf("%w: %q", ErrUnknownColumn, by)
+	}
+
+	sort.SliceStable(rs.records, func(i, j int) bool {
+		a, b := rs.records[i].values[by], rs.records[j].values[by]
+		if desc {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+
+	return nil
+}
+
+func contains(values []string, v string) bool {
+	for _, c := range values {
+		if c == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// less orders nil values first, then numbers and finally strings.
+func less(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+
+	if na, ok := a.(json.Number); ok {
+		if nb, ok := b.(json.Number); ok {
+			fa, _ := na.Float64()
+			fb, _ := nb.Float64()
+			return fa < fb
+		}
+	}
+
+	return format(a) < format(b)
+}
+
+// format converts a decoded JSON value into its textual form.
+func format(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return fmt.Sprint(t)
+	}
+
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
+
+func (r *record) row(empty string) []string {
+	row := make([]string, len(r.columns))
+	for i, c := range r.columns {
+		if s := format(r.values[c]); s != "" {
+			row[i] = s
+		} else {
+			row[i] = empty
+		}
+	}
+
+	return row
+}
+
+// MarshalJSON encodes the record keeping the columns order.
+func (r *record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range r.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, _ := json.Marshal(c)
+		v, err := json.Marshal(r.values[c])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML encodes the record keeping the columns order.
+func (r *record) MarshalYAML() (interface{}, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode}
+	for _, c := range r.columns {
+		b, err := yaml.Marshal(yamlValue(r.values[c]))
+		if err != nil {
+			return nil, err
+		}
+
+		var v yaml.Node
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+
+		n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: c}, v.Content[0])
+	}
+
+	return n, nil
+}
+
+// yamlValue converts json numbers so they are not quoted.
+func yamlValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i := range t {
+			out[i] = yamlValue(t[i])
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k := range t {
+			out[k] = yamlValue(t[k])
+		}
+		return out
+	}
+
+	return v
+}
+
+func (rs *recordSet) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	headers := make([]string, len(rs.columns))
+	for i, c := range rs.columns {
+		headers[i] = strings.ToUpper(c)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, r := range rs.records {
+		fmt.Fprintln(tw, strings.Join(r.row("-"), "\t"))
+	}
+
+	return tw.Flush()
+}
+
+func (rs *recordSet) writeDetails(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, r := range rs.records {
+		for i, v := range r.row("-") {
+			fmt.Fprintf(tw, "%s\t%s\n", strings.ToUpper(r.columns[i]), v)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (rs *recordSet) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	if rs.single {
+		return enc.Encode(rs.records[0])
+	}
+
+	if rs.records == nil {
+		return enc.Encode([]*record{})
+	}
+
+	return enc.Encode(rs.records)
+}
+
+func (rs *recordSet) writeNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, r := range rs.records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rs *recordSet) writeYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	var err error
+	switch {
+	case rs.single:
+		err = enc.Encode(rs.records[0])
+	case rs.records == nil:
+		err = enc.Encode([]*record{})
+	default:
+		err = enc.Encode(rs.records)
+	}
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func (rs *recordSet) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(rs.columns)
+	for _, r := range rs.records {
+		_ = cw.Write(r.row(""))
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func (rs *recordSet) writeTemplate(w io.Writer, text string) error {
+	if text == "" {
+		return ErrEmptyTemplate
+	}
+
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	tpl, err := template.New("item").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	for _, r := range rs.records {
+		fields := make(map[string]interface{}, len(r.values))
+		for k, v := range r.values {
+			fields[k] = v
+		}
+
+		if err := tpl.Execute(w, fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
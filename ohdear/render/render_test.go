@@ -0,0 +1,145 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+func sites() []*ohdear.Site {
+	return []*ohdear.Site{
+		{ID: 2, URL: "https://b.tld", Label: "b", SummarizedChecksResult: "failed"},
+		{ID: 10, URL: "https://a.tld", SummarizedChecksResult: "succeeded"},
+		{ID: 1, URL: "http://c.tld", Label: "c"},
+	}
+}
+
+func TestRender(t *testing.T) {
+	columns := []string{"id", "url", "summarized_check_result"}
+
+	cases := []struct {
+		name string
+		in   interface{}
+		opts Options
+		want string
+	}{
+		{
+			"table sorted by numeric id",
+			sites(),
+			Options{Format: Table, Columns: columns, SortBy: "id"},
+			"ID  URL            SUMMARIZED_CHECK_RESULT\n" +
+				"1   http://c.tld   -\n" +
+				"2   https://b.tld  failed\n" +
+				"10  https://a.tld  succeeded\n",
+		},
+		{
+			"csv sorted descending by a column which is not rendered",
+			sites(),
+			Options{Format: CSV, Columns: []string{"id", "url"}, SortBy: "-label"},
+			"id,url\n1,http://c.tld\n2,https://b.tld\n10,https://a.tld\n",
+		},
+		{
+			"ndjson keeps the columns order",
+			sites()[:2],
+			Options{Format: NDJSON, Columns: []string{"url", "id"}},
+			`{"url":"https://b.tld","id":2}` + "\n" + `{"url":"https://a.tld","id":10}` + "\n",
+		},
+		{
+			"json list",
+			sites()[:1],
+			Options{Format: JSON, Columns: []string{"id", "label"}},
+			"[\n  {\n    \"id\": 2,\n    \"label\": \"b\"\n  }\n]\n",
+		},
+		{
+			"json single item",
+			sites()[0],
+			Options{Format: JSON, Columns: []string{"id"}},
+			"{\n  \"id\": 2\n}\n",
+		},
+		{
+			"json empty list",
+			[]*ohdear.Site{},
+			Options{Format: JSON},
+			"[]\n",
+		},
+		{
+			"yaml list",
+			sites()[:2],
+			Options{Format: YAML, Columns: []string{"id", "url"}},
+			"- id: 2\n  url: https://b.tld\n- id: 10\n  url: https://a.tld\n",
+		},
+		{
+			"template per item",
+			sites(),
+			Options{Format: Template, Template: "{{.id}} {{.url}}", SortBy: "url"},
+			"1 http://c.tld\n10 https://a.tld\n2 https://b.tld\n",
+		},
+		{
+			"single item details",
+			&ohdear.UptimePerDatetime{
+				Datetime:         &ohdear.CustomDate{Time: time.Date(2020, time.May, 1, 12, 0, 0, 0, time.UTC)},
+				UptimePercentage: 99.5,
+			},
+			Options{},
+			"DATETIME           2020-05-01 12:00:00\nUPTIME_PERCENTAGE  99.5\n",
+		},
+		{
+			"downtime periods with open ends",
+			[]*ohdear.DowntimePeriods{
+				{StartedAt: &ohdear.CustomDate{Time: time.Date(2020, time.May, 1, 12, 0, 0, 0, time.UTC)}},
+			},
+			Options{Format: CSV},
+			"started_at,ended_at\n2020-05-01 12:00:00,\n",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, c.in, c.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, c.want, buf.String())
+		})
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		in   interface{}
+		opts Options
+		want error
+	}{
+		{"unknown column", sites(), Options{Columns: []string{"nope"}}, ErrUnknownColumn},
+		{"unknown sort column", sites(), Options{SortBy: "-nope"}, ErrUnknownColumn},
+		{"unknown format", sites(), Options{Format: "xml"}, ErrUnknownFormat},
+		{"missing template", sites(), Options{Format: Template}, ErrEmptyTemplate},
+		{"invalid template", sites(), Options{Format: Template, Template: "{{.id"}, ErrInvalidTemplate},
+		{"unsupported kind", []string{"a"}, Options{}, ErrUnsupportedKind},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.True(t, errors.Is(Render(&buf, c.in, c.opts), c.want))
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("CSV")
+	assert.Nil(t, err)
+	assert.Equal(t, CSV, f)
+
+	_, err = ParseFormat("xml")
+	assert.True(t, errors.Is(err, ErrUnknownFormat))
+}
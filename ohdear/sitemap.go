@@ -0,0 +1,141 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+// SitemapBasePath is the resource path prefix.
+const SitemapBasePath string = "sitemap"
+
+// SitemapSrv operates over the sitemap check results of a site.
+type SitemapSrv srv
+
+// SitemapIssueType classifies the problems found by the sitemap check.
+type SitemapIssueType string
+
+// Supported SitemapIssueType values.
+const (
+	SitemapUnreachable SitemapIssueType = "unreachable_sitemap"
+	SitemapInvalidXML  SitemapIssueType = "invalid_xml"
+	SitemapURLError    SitemapIssueType = "url_error"
+)
+
+// SitemapIssue describes a problem with a sitemap or one of its urls,
+// URL and StatusCode are only set for url errors.
+type SitemapIssue struct {
+	Type       SitemapIssueType `json:"type,omitempty"`
+	Name       string           `json:"name,omitempty"`
+	SitemapURL string           `json:"sitemap_url,omitempty"`
+	URL        string           `json:"url,omitempty"`
+	StatusCode int              `json:"status_code,omitempty"`
+}
+
+// SitemapFile is a sitemap, or sitemap index, reached by the check.
+type SitemapFile struct {
+	URL      string          `json:"url,omitempty"`
+	URLCount uint            `json:"url_count"`
+	Issues   []*SitemapIssue `json:"issues,omitempty"`
+}
+
+// SitemapResult summarizes the latest sitemap check of a site.
+type SitemapResult struct {
+	CheckURL         string          `json:"check_url,omitempty"`
+	TotalURLCount    uint            `json:"total_url_count"`
+	TotalIssuesCount uint            `json:"total_issues_count"`
+	SitemapIndexes   []*SitemapFile  `json:"sitemap_indexes,omitempty"`
+	Sitemaps         []*SitemapFile  `json:"sitemaps,omitempty"`
+	Issues           []*SitemapIssue `json:"issues,omitempty"`
+	CheckedAt        *CustomDate     `json:"checked_at,omitempty"`
+}
+
+// SitemapIssuesResponse is a page of sitemap issues
+// inside an outer data wrapper.
+type SitemapIssuesResponse struct {
+	Data  []*SitemapIssue  `json:"data"`
+	Links *PaginationLinks `json:"links,omitempty"`
+	Meta  *PaginationMeta  `json:"meta,omitempty"`
+}
+
+// SitemapRequestFilters adds the pagination parameters and the
+// issue type filter to sitemap issues requests.
+//
+// None of the values are required.
+type SitemapRequestFilters struct {
+	PageSize   uint             `url:"page[size],omitempty"`
+	PageNumber uint             `url:"page[number],omitempty"`
+	Type       SitemapIssueType `url:"filter[type],omitempty"`
+}
+
+// Get returns the latest sitemap check results of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#sitemap
+func (ss *SitemapSrv) Get(siteID uint) (sr *SitemapResult, err error) {
+	req, err := ss.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d", SitemapBasePath, siteID),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ss.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &sr); err != nil {
+		return
+	}
+
+	return
+}
+
+// Issues returns a page of the issues found by the sitemap check of a site.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#sitemap
+func (ss *SitemapSrv) Issues(siteID uint, filters SitemapRequestFilters) (ir *SitemapIssuesResponse, err error) {
+	q, _ := query.Values(filters)
+	req, err := ss.client.NewAPIRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/%d/issues?%s", SitemapBasePath, siteID, q.Encode()),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ss.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &ir); err != nil {
+		return
+	}
+
+	return
+}
+
+// AllIssues walks every page of sitemap issues of a site,
+// an empty type returns the issues of every type.
+func (ss *SitemapSrv) AllIssues(siteID uint, t SitemapIssueType) (issues []*SitemapIssue, err error) {
+	filters := SitemapRequestFilters{PageNumber: 1, Type: t}
+	for {
+		ir, err := ss.Issues(siteID, filters)
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, ir.Data...)
+		if !ir.Meta.HasNextPage() {
+			return issues, nil
+		}
+
+		filters.PageNumber++
+	}
+}
@@ -0,0 +1,132 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestSitemapSrv_Get(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sitemap/1", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.SitemapResponse)
+	})
+
+	got, err := tClient.Sitemap.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint(124), got.TotalURLCount)
+	assert.Equal(t, uint(2), got.TotalIssuesCount)
+	assert.Len(t, got.SitemapIndexes, 1)
+	assert.Len(t, got.Sitemaps, 2)
+	assert.Equal(t, SitemapURLError, got.Sitemaps[0].Issues[0].Type)
+	assert.Equal(t, http.StatusNotFound, got.Sitemaps[0].Issues[0].StatusCode)
+	assert.Equal(t, SitemapInvalidXML, got.Sitemaps[1].Issues[0].Type)
+}
+
+func TestSitemapSrv_AllIssues(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sitemap/1/issues", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Empty(t, r.URL.Query().Get("filter[type]"))
+
+		w.WriteHeader(http.StatusOK)
+		if r.URL.Query().Get("page[number]") == "2" {
+			_, _ = fmt.Fprint(w, testdata.SitemapIssuesPage2Response)
+			return
+		}
+		_, _ = fmt.Fprint(w, testdata.SitemapIssuesPage1Response)
+	})
+
+	got, err := tClient.Sitemap.AllIssues(1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "https://yoursite.tld/posts/removed", got[0].URL)
+	assert.Equal(t, "https://yoursite.tld/sitemap-pages.xml", got[1].SitemapURL)
+}
+
+func TestSitemapSrv_Issues(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sitemap/1/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "url_error", r.URL.Query().Get("filter[type]"))
+		assert.Equal(t, "1", r.URL.Query().Get("page[size]"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.SitemapIssuesPage1Response)
+	})
+
+	got, err := tClient.Sitemap.Issues(1, SitemapRequestFilters{PageSize: 1, Type: SitemapURLError})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 1)
+	assert.True(t, got.Meta.HasNextPage())
+}
+
+func TestSitesSrv_UpdateSitemapSettings(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/update-sitemap-settings", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+
+		b, _ := ioutil.ReadAll(r.Body)
+		var body SitemapSettingsRequest
+		_ = json.Unmarshal(b, &body)
+		assert.Equal(t, "/sitemap_index.xml", body.SitemapPath)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, strings.Replace(testdata.SingleSiteResponse, `"id": 1,`, `"id": 1, "sitemap_path": "/sitemap_index.xml",`, 1))
+	})
+
+	got, err := tClient.Sites.UpdateSitemapSettings(1, SitemapSettingsRequest{SitemapPath: "/sitemap_index.xml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "/sitemap_index.xml", got.SitemapPath)
+
+	for _, p := range []string{"", "sitemap.xml", "https://yoursite.tld/sitemap.xml", "//cdn.tld/sitemap.xml"} {
+		_, err = tClient.Sites.UpdateSitemapSettings(1, SitemapSettingsRequest{SitemapPath: p})
+		assert.True(t, errors.Is(err, ErrInvalidSitemapPath), p)
+	}
+}
@@ -1,43 +1,118 @@
 package ohdear
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
-	"net/url"
+	"strconv"
 
 	"github.com/google/go-querystring/query"
 )
 
 // SitesBasePath is the resource path prefix.
-const SitesBasePath string = "/sites"
+const SitesBasePath string = "sites"
+
+// sitesPageSize is the page size used by SitesSrv.ListAll
+// when the filters do not set one.
+const sitesPageSize uint = 50
 
 // SitesSrv operates over the site resource
 type SitesSrv srv
 
-// Site represents a monitored website and its properties.
+// Site represents a monitored website and its properties, the
+// host, port and tcp expectation are only used by ping and tcp monitors.
 type Site struct {
-	ID                                   uint        `json:"id,omitempty"`
-	URL                                  string      `json:"url,omitempty"`
-	SortURL                              string      `json:"sort_url,omitempty"`
-	Label                                string      `json:"label,omitempty"`
-	TeamID                               uint        `json:"team_id,omitempty"`
-	LatestRunDate                        *CustomDate `json:"latest_run_date,omitempty"`
-	CreatedAt                            *CustomDate `json:"created_at,omitempty"`
-	UpdatedAt                            *CustomDate `json:"updated_at,omitempty"`
-	Checks                               []struct{}  `json:"checks,omitempty"`
-	SummarizedChecksResult               string      `json:"summarized_checks_result,omitempty"`
-	FriendlyName                         string      `json:"friendly_name,omitempty"`
-	UsesHTTPS                            bool        `json:"uses_https,omitempty"`
-	BrokenLinksCheckIncludeExternalLinks bool        `json:"broken_links_check_include_external_links,omitempty"`
-	BrokenLinksWhitelistedURLS           []*url.URL  `json:"broken_links_whitelisted_urls,omitempty"`
+	ID                                   uint           `json:"id,omitempty"`
+	Type                                 MonitorType    `json:"type,omitempty"`
+	URL                                  string         `json:"url,omitempty"`
+	Host                                 string         `json:"host,omitempty"`
+	Port                                 uint           `json:"port,omitempty"`
+	TCPExpectation                       TCPExpectation `json:"tcp_expectation,omitempty"`
+	SortURL                              string         `json:"sort_url,omitempty"`
+	Label                                string         `json:"label,omitempty"`
+	TeamID                               uint           `json:"team_id,omitempty"`
+	LatestRunDate                        *CustomDate    `json:"latest_run_date,omitempty"`
+	CreatedAt                            *CustomDate    `json:"created_at,omitempty"`
+	UpdatedAt                            *CustomDate    `json:"updated_at,omitempty"`
+	Checks                               []*SiteCheck   `json:"checks,omitempty"`
+	SummarizedChecksResult               string         `json:"summarized_check_result,omitempty"`
+	FriendlyName                         string         `json:"friendly_name,omitempty"`
+	UsesHTTPS                            bool           `json:"uses_https,omitempty"`
+	BrokenLinksCheckIncludeExternalLinks bool           `json:"broken_links_check_include_external_links,omitempty"`
+	BrokenLinksWhitelistedURLS           []string       `json:"broken_links_whitelisted_urls,omitempty"`
+	SitemapPath                          string         `json:"sitemap_path,omitempty"`
+}
+
+// Kind returns the monitor type of the site, sites
+// without type are http monitors.
+func (s *Site) Kind() MonitorType {
+	if s.Type == "" {
+		return MonitorHTTP
+	}
+
+	return s.Type
+}
+
+// Target returns the monitored url for http monitors, the host
+// for ping monitors and the host and port for tcp monitors.
+func (s *Site) Target() string {
+	switch s.Kind() {
+	case MonitorPing:
+		return s.Host
+	case MonitorTCP:
+		return net.JoinHostPort(s.Host, strconv.FormatUint(uint64(s.Port), 10))
+	}
+
+	return s.URL
 }
 
 // List returns all the sites in your account.
 //
 // See: https://ohdear.app/docs/integrations/api/sites#get-all-sites-in-your-account
 func (ss *SitesSrv) List(filters ListSitesRequestFilters) (sites []*Site, err error) {
+	sr, err := ss.list(context.Background(), filters)
+	if err != nil {
+		return
+	}
+
+	return sr.Data, nil
+}
+
+// ListAll walks every page of sites matching the filters, starting
+// at the filters page number, while the pagination meta reports a
+// next page. The context is checked before requesting each page.
+func (ss *SitesSrv) ListAll(ctx context.Context, filters ListSitesRequestFilters) (sites []*Site, err error) {
+	if filters.PageSize == 0 {
+		filters.PageSize = sitesPageSize
+	}
+
+	if filters.PageNumber == 0 {
+		filters.PageNumber = 1
+	}
+
+	for {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		sr, err := ss.list(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+
+		sites = append(sites, sr.Data...)
+		if !sr.Meta.HasNextPage() {
+			return sites, nil
+		}
+
+		filters.PageNumber++
+	}
+}
+
+func (ss *SitesSrv) list(ctx context.Context, filters ListSitesRequestFilters) (sr *sitesResponse, err error) {
 	q, _ := query.Values(filters)
 	req, err := ss.client.NewAPIRequest(
 		http.MethodGet,
@@ -48,12 +123,12 @@ func (ss *SitesSrv) List(filters ListSitesRequestFilters) (sites []*Site, err er
 		return
 	}
 
-	res, err := ss.client.Do(req)
+	res, err := ss.client.Do(req.WithContext(ctx))
 	if err != nil {
 		return
 	}
 
-	if err = json.Unmarshal(res.content, &sites); err != nil {
+	if err = json.Unmarshal(res.content, &sr); err != nil {
 		return
 	}
 
@@ -81,6 +156,67 @@ func (ss *SitesSrv) Create(s Site) (site *Site, err error) {
 	return
 }
 
+// CreateWithSettings adds a new site to your account configuring
+// its monitor type, checks and uptime settings.
+//
+// See: https://ohdear.app/docs/integrations/api/sites#add-a-site-through-the-api
+func (ss *SitesSrv) CreateWithSettings(ctx context.Context, settings SiteSettings) (site *Site, err error) {
+	if err = settings.validateRequired(); err != nil {
+		return
+	}
+
+	if err = settings.Validate(); err != nil {
+		return
+	}
+
+	settings.normalize()
+	req, err := ss.client.NewAPIRequest(http.MethodPost, SitesBasePath, settings)
+	if err != nil {
+		return
+	}
+
+	res, err := ss.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &site); err != nil {
+		return
+	}
+
+	return
+}
+
+// Update changes the settings of an existing site.
+//
+// See: https://ohdear.app/docs/integrations/api/sites
+func (ss *SitesSrv) Update(ctx context.Context, id uint, settings SiteSettings) (site *Site, err error) {
+	if err = settings.Validate(); err != nil {
+		return
+	}
+
+	settings.normalize()
+	req, err := ss.client.NewAPIRequest(
+		http.MethodPut,
+		fmt.Sprintf("%s/%d", SitesBasePath, id),
+		settings,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ss.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &site); err != nil {
+		return
+	}
+
+	return
+}
+
 // Get retrieves a specific site by its ID.
 //
 // See: https://ohdear.app/docs/integrations/api/sites#get-a-specific-site-via-the-api
@@ -211,6 +347,10 @@ func (ss *SitesSrv) GetUptimePercentage(id uint, filters UptimeRequestFilters) (
 //
 // See: https://ohdear.app/docs/integrations/api/sites#adding-urls-to-the-broken-links-whitelist
 func (ss *SitesSrv) AddToBrokenLinkWhitelist(id uint, url string) (site *Site, err error) {
+	if err = ValidateWhitelistURL(url); err != nil {
+		return
+	}
+
 	body := WhitelistURLRequest{
 		WhitelistURL: url,
 	}
@@ -256,3 +396,137 @@ func (ss *SitesSrv) UpdateBrokenLinksSettings(id uint, body BrokenLinksSettingsR
 
 	return
 }
+
+// UpdateSitemapSettings changes the sitemap monitored for a site,
+// the results are available through SitemapSrv.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#sitemap
+func (ss *SitesSrv) UpdateSitemapSettings(id uint, body SitemapSettingsRequest) (site *Site, err error) {
+	if err = body.Validate(); err != nil {
+		return
+	}
+
+	req, err := ss.client.NewAPIRequest(
+		http.MethodPut,
+		fmt.Sprintf("%s/%d/update-sitemap-settings", SitesBasePath, id),
+		body,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ss.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &site); err != nil {
+		return
+	}
+
+	return
+}
+
+// GetBrokenLinksWhitelist returns the urls ignored by the broken links
+// check of a given site.
+func (ss *SitesSrv) GetBrokenLinksWhitelist(id uint) (urls []string, err error) {
+	site, err := ss.Get(id)
+	if err != nil {
+		return
+	}
+
+	urls = site.BrokenLinksWhitelistedURLS
+
+	return
+}
+
+// AddManyToBrokenLinkWhitelist extends the whitelist of a given site
+// with all the provided urls in a single request.
+//
+// Urls already present in the whitelist, or repeated in urls, are not
+// duplicated. The whitelist is read and written in two requests, so
+// concurrent updates of the same site can overwrite each other.
+func (ss *SitesSrv) AddManyToBrokenLinkWhitelist(id uint, urls ...string) (site *Site, err error) {
+	if err = validateWhitelistURLs(urls); err != nil {
+		return
+	}
+
+	current, err := ss.Get(id)
+	if err != nil {
+		return
+	}
+
+	return ss.replaceBrokenLinksWhitelist(id, current, uniqueStrings(append(current.BrokenLinksWhitelistedURLS, urls...)))
+}
+
+// RemoveFromBrokenLinkWhitelist removes the provided urls from the
+// whitelist of a given site.
+//
+// The whitelist is read and written in two requests, so concurrent
+// updates of the same site can overwrite each other.
+func (ss *SitesSrv) RemoveFromBrokenLinkWhitelist(id uint, urls ...string) (site *Site, err error) {
+	current, err := ss.Get(id)
+	if err != nil {
+		return
+	}
+
+	remove := make(map[string]struct{}, len(urls))
+	for _, u := range urls {
+		remove[u] = struct{}{}
+	}
+
+	kept := make([]string, 0, len(current.BrokenLinksWhitelistedURLS))
+	for _, u := range current.BrokenLinksWhitelistedURLS {
+		if _, ok := remove[u]; !ok {
+			kept = append(kept, u)
+		}
+	}
+
+	return ss.replaceBrokenLinksWhitelist(id, current, kept)
+}
+
+// ReplaceBrokenLinksWhitelist overrides the whitelist of a given site
+// with the provided urls, an empty list clears the whitelist.
+//
+// See: https://ohdear.app/docs/integrations/api/sites#broken-links-settings
+func (ss *SitesSrv) ReplaceBrokenLinksWhitelist(id uint, urls ...string) (site *Site, err error) {
+	if err = validateWhitelistURLs(urls); err != nil {
+		return
+	}
+
+	current, err := ss.Get(id)
+	if err != nil {
+		return
+	}
+
+	return ss.replaceBrokenLinksWhitelist(id, current, uniqueStrings(urls))
+}
+
+// replaceBrokenLinksWhitelist sends the urls along with the current
+// external links setting of the site, so the setting is not reset.
+func (ss *SitesSrv) replaceBrokenLinksWhitelist(id uint, current *Site, urls []string) (site *Site, err error) {
+	body := brokenLinksWhitelistRequest{
+		BrokenLinksCheckIncludeExternalLinks: current.BrokenLinksCheckIncludeExternalLinks,
+		BrokenLinksWhitelistedURLS:           urls,
+	}
+
+	req, err := ss.client.NewAPIRequest(
+		http.MethodPut,
+		fmt.Sprintf("%s/%d/update-broken-links-settings", SitesBasePath, id),
+		body,
+	)
+	if err != nil {
+		return
+	}
+
+	res, err := ss.client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &site); err != nil {
+		return
+	}
+
+	return
+}
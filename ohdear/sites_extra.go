@@ -1,6 +1,12 @@
 package ohdear
 
-import "time"
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
 
 // ListSitesRequestFilters adds the required query string
 // parameters to control the response values of a list sites
@@ -11,10 +17,18 @@ import "time"
 //
 // Non of the values are required.
 type ListSitesRequestFilters struct {
-	PageSize       uint   `url:"page[size],omitempty"`
-	PageNumber     uint   `url:"page[number],omitempty"`
-	SortBy         string `url:"sort,omitempty"`
-	FilterByTeamID uint   `url:"filter[team_id],omitempty"`
+	PageSize       uint        `url:"page[size],omitempty"`
+	PageNumber     uint        `url:"page[number],omitempty"`
+	SortBy         string      `url:"sort,omitempty"`
+	FilterByTeamID uint        `url:"filter[team_id],omitempty"`
+	FilterByType   MonitorType `url:"filter[type],omitempty"`
+}
+
+// sitesResponse is the outer data wrapper of the list sites response.
+type sitesResponse struct {
+	Data  []*Site          `json:"data"`
+	Links *PaginationLinks `json:"links,omitempty"`
+	Meta  *PaginationMeta  `json:"meta,omitempty"`
 }
 
 // WhitelistURLRequest generates the correct json body
@@ -32,8 +46,8 @@ type UptimeResponse struct {
 // UptimePerDatetime describes the individual values returned for
 // site uptime responses.
 type UptimePerDatetime struct {
-	Datetime         *time.Time `json:"datetime"`
-	UptimePercentage float64    `json:"uptime_percentage"`
+	Datetime         *CustomDate `json:"datetime"`
+	UptimePercentage float64     `json:"uptime_percentage"`
 }
 
 // UptimeRequestFilters adds the required filters to
@@ -51,8 +65,8 @@ type UptimeRequestFilters struct {
 // DowntimePeriods describes the individual values returned for
 // site downtime responses.
 type DowntimePeriods struct {
-	StartedAt *time.Time `json:"started_at"`
-	EndedAt   *time.Time `json:"ended_at"`
+	StartedAt *CustomDate `json:"started_at"`
+	EndedAt   *CustomDate `json:"ended_at"`
 }
 
 // DowntimeResponse is an array of values inside an outer data wrapper.
@@ -86,6 +100,311 @@ const (
 //
 // Values are not required and should only be sent when updated.
 type BrokenLinksSettingsRequest struct {
-	BrokenLinksCheckIncludeExternalLinks bool   `json:"broken_links_check_include_external_links,omitempty"`
-	BrokenLinksWhitelistedURLS           string `json:"broken_links_whitelisted_urls,omitempty"`
+	BrokenLinksCheckIncludeExternalLinks bool     `json:"broken_links_check_include_external_links,omitempty"`
+	BrokenLinksWhitelistedURLS           []string `json:"broken_links_whitelisted_urls,omitempty"`
+}
+
+// brokenLinksWhitelistRequest always sends the whitelist, even when
+// empty, so it can be used to clear it, and the external links setting
+// so it keeps its value.
+type brokenLinksWhitelistRequest struct {
+	BrokenLinksCheckIncludeExternalLinks bool     `json:"broken_links_check_include_external_links"`
+	BrokenLinksWhitelistedURLS           []string `json:"broken_links_whitelisted_urls"`
+}
+
+// ValidateWhitelistURL checks that the given value is an absolute
+// http(s) url which can be added to a broken links whitelist.
+func ValidateWhitelistURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidWhitelistURL, raw)
+	}
+
+	return nil
+}
+
+// SitemapSettingsRequest describes the request body required
+// to change the sitemap monitored for a site.
+//
+// SitemapPath is relative to the site url, like /sitemap.xml.
+type SitemapSettingsRequest struct {
+	SitemapPath string `json:"sitemap_path"`
+}
+
+// Validate checks that the sitemap path is an absolute path
+// without scheme nor host.
+func (s SitemapSettingsRequest) Validate() error {
+	u, err := url.Parse(s.SitemapPath)
+	if err != nil || !strings.HasPrefix(s.SitemapPath, "/") || strings.HasPrefix(s.SitemapPath, "//") || u.Host != "" {
+		return fmt.Errorf("%w: %q", ErrInvalidSitemapPath, s.SitemapPath)
+	}
+
+	return nil
+}
+
+func validateWhitelistURLs(urls []string) error {
+	for _, u := range urls {
+		if err := ValidateWhitelistURL(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// uniqueStrings removes duplicated values keeping the first occurrence,
+// it never returns nil.
+func uniqueStrings(in []string) []string {
+	seen := make(map[string]struct{}, len(in))
+	out := make([]string, 0, len(in))
+	for _, v := range in {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+
+	return out
+}
+
+// CheckType identifies one of the checks Oh Dear can run on a site.
+type CheckType string
+
+// Supported CheckType values.
+const (
+	UptimeCheck                  CheckType = "uptime"
+	BrokenLinksCheck             CheckType = "broken_links"
+	MixedContentCheck            CheckType = "mixed_content"
+	CertificateHealthCheck       CheckType = "certificate_health"
+	CertificateTransparencyCheck CheckType = "certificate_transparency"
+	PerformanceCheck             CheckType = "performance"
+	ApplicationHealthCheckType   CheckType = "application_health"
+	DNSCheck                     CheckType = "dns"
+	DomainCheck                  CheckType = "domain"
+	SitemapCheck                 CheckType = "sitemap"
+	LighthouseCheck              CheckType = "lighthouse"
+)
+
+// CheckResult is the outcome of the latest run of a check.
+type CheckResult string
+
+// Supported CheckResult values.
+const (
+	CheckSucceeded CheckResult = "succeeded"
+	CheckWarning   CheckResult = "warning"
+	CheckFailed    CheckResult = "failed"
+	CheckPending   CheckResult = "pending"
+	CheckErrored   CheckResult = "errored"
+)
+
+// CheckResults lists the known check results.
+var CheckResults = []CheckResult{CheckSucceeded, CheckWarning, CheckFailed, CheckPending, CheckErrored}
+
+// SiteCheck describes a check enabled on a site and its latest result,
+// both values are nil until the check runs for the first time.
+type SiteCheck struct {
+	ID               uint        `json:"id,omitempty"`
+	Type             CheckType   `json:"type,omitempty"`
+	Label            string      `json:"label,omitempty"`
+	Enabled          bool        `json:"enabled"`
+	LatestRunEndedAt *CustomDate `json:"latest_run_ended_at,omitempty"`
+	LatestRunResult  CheckResult `json:"latest_run_result,omitempty"`
+}
+
+// MonitorType is the kind of target monitored by a site.
+type MonitorType string
+
+// Supported MonitorType values, sites without type are http monitors.
+const (
+	MonitorHTTP MonitorType = "http"
+	MonitorPing MonitorType = "ping"
+	MonitorTCP  MonitorType = "tcp"
+)
+
+// MonitorTypes lists the known monitor types.
+var MonitorTypes = []MonitorType{MonitorHTTP, MonitorPing, MonitorTCP}
+
+// TCPExpectation is the connection behavior expected by a tcp monitor,
+// a closed expectation alerts when the port becomes reachable.
+type TCPExpectation string
+
+// Supported TCPExpectation values.
+const (
+	TCPExpectOpen   TCPExpectation = "open"
+	TCPExpectClosed TCPExpectation = "closed"
+)
+
+// HTTPField is a name and value pair used for the headers and
+// the payload sent by the uptime check.
+type HTTPField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SiteSettings describes the request body used to create or
+// update a site including its uptime check configuration.
+//
+// Values are not required and should only be sent when updated.
+// When creating a site the url is mandatory for http monitors,
+// the host for ping monitors and the host and port for tcp monitors.
+type SiteSettings struct {
+	Type                            MonitorType    `json:"type,omitempty"`
+	URL                             string         `json:"url,omitempty"`
+	Host                            string         `json:"host,omitempty"`
+	Port                            uint           `json:"port,omitempty"`
+	TCPExpectation                  TCPExpectation `json:"tcp_expectation,omitempty"`
+	TeamID                          uint           `json:"team_id,omitempty"`
+	Label                           string         `json:"label,omitempty"`
+	FriendlyName                    string         `json:"friendly_name,omitempty"`
+	Checks                          []CheckType    `json:"checks,omitempty"`
+	UptimeCheckLocation             string         `json:"uptime_check_location,omitempty"`
+	UptimeCheckMethod               string         `json:"uptime_check_method,omitempty"`
+	UptimeCheckExpectedResponseCode int            `json:"uptime_check_expected_response_code,omitempty"`
+	UptimeCheckHeaders              []*HTTPField   `json:"uptime_check_headers,omitempty"`
+	UptimeCheckPayload              []*HTTPField   `json:"uptime_check_payload,omitempty"`
+	UptimeCheckLookForString        string         `json:"uptime_check_look_for_string,omitempty"`
+	UptimeCheckAbsentString         string         `json:"uptime_check_absent_string,omitempty"`
+	UptimeCheckTimeoutInSeconds     uint           `json:"uptime_check_timeout_in_seconds,omitempty"`
+	UptimeCheckFollowRedirects      *bool          `json:"uptime_check_follow_redirects,omitempty"`
+}
+
+// Validate checks the settings values before sending them to the API.
+//
+// The settings specific to a monitor type are rejected when
+// another type is given.
+func (s *SiteSettings) Validate() error {
+	if err := s.validateMonitor(); err != nil {
+		return err
+	}
+
+	if s.URL != "" {
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: invalid url %q", ErrInvalidSiteSettings, s.URL)
+		}
+	}
+
+	switch strings.ToUpper(s.UptimeCheckMethod) {
+	case "", http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fmt.Errorf("%w: unsupported http method %q", ErrInvalidSiteSettings, s.UptimeCheckMethod)
+	}
+
+	if c := s.UptimeCheckExpectedResponseCode; c != 0 && (c < 100 || c > 599) {
+		return fmt.Errorf("%w: invalid expected response code %d", ErrInvalidSiteSettings, c)
+	}
+
+	for _, h := range s.UptimeCheckHeaders {
+		if h == nil || strings.TrimSpace(h.Name) == "" {
+			return fmt.Errorf("%w: headers must have a name", ErrInvalidSiteSettings)
+		}
+	}
+
+	return nil
+}
+
+// normalize sends the uptime check method in upper case,
+// as it is accepted in any case by Validate.
+func (s *SiteSettings) normalize() {
+	s.UptimeCheckMethod = strings.ToUpper(s.UptimeCheckMethod)
+}
+
+func (s *SiteSettings) validateMonitor() error {
+	switch s.Type {
+	case "", MonitorHTTP, MonitorPing, MonitorTCP:
+	default:
+		return fmt.Errorf("%w: unknown monitor type %q, expected one of %v", ErrInvalidSiteSettings, s.Type, MonitorTypes)
+	}
+
+	if s.Host != "" && !validHost(s.Host) {
+		return fmt.Errorf("%w: invalid host %q", ErrInvalidSiteSettings, s.Host)
+	}
+
+	if s.Port > 65535 {
+		return fmt.Errorf("%w: invalid port %d", ErrInvalidSiteSettings, s.Port)
+	}
+
+	switch s.TCPExpectation {
+	case "", TCPExpectOpen, TCPExpectClosed:
+	default:
+		return fmt.Errorf("%w: unknown tcp expectation %q", ErrInvalidSiteSettings, s.TCPExpectation)
+	}
+
+	switch s.Type {
+	case MonitorHTTP:
+		if s.Host != "" || s.Port != 0 || s.TCPExpectation != "" {
+			return fmt.Errorf("%w: http monitors use an url instead of host and port", ErrInvalidSiteSettings)
+		}
+	case MonitorPing:
+		if s.Port != 0 || s.TCPExpectation != "" {
+			return fmt.Errorf("%w: ping monitors do not use a port", ErrInvalidSiteSettings)
+		}
+	}
+
+	if s.Type == MonitorPing || s.Type == MonitorTCP {
+		if s.URL != "" || s.hasHTTPSettings() {
+			return fmt.Errorf("%w: %s monitors do not support url nor http settings", ErrInvalidSiteSettings, s.Type)
+		}
+
+		for _, c := range s.Checks {
+			if c != UptimeCheck {
+				return fmt.Errorf("%w: %s monitors only support the %s check", ErrInvalidSiteSettings, s.Type, UptimeCheck)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRequired checks the values required to create a site.
+func (s *SiteSettings) validateRequired() error {
+	switch s.Type {
+	case MonitorPing:
+		if s.Host == "" {
+			return fmt.Errorf("%w: host is required", ErrInvalidSiteSettings)
+		}
+	case MonitorTCP:
+		if s.Host == "" || s.Port == 0 {
+			return fmt.Errorf("%w: host and port are required", ErrInvalidSiteSettings)
+		}
+	default:
+		if s.URL == "" {
+			return fmt.Errorf("%w: url is required", ErrInvalidSiteSettings)
+		}
+	}
+
+	return nil
+}
+
+func (s *SiteSettings) hasHTTPSettings() bool {
+	return s.UptimeCheckMethod != "" ||
+		s.UptimeCheckExpectedResponseCode != 0 ||
+		len(s.UptimeCheckHeaders) > 0 ||
+		len(s.UptimeCheckPayload) > 0 ||
+		s.UptimeCheckLookForString != "" ||
+		s.UptimeCheckAbsentString != "" ||
+		s.UptimeCheckFollowRedirects != nil
+}
+
+// validHost accepts ip addresses and host names without
+// scheme, port nor path.
+func validHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	if len(host) > 253 || strings.HasPrefix(host, ".") || strings.Contains(host, "..") {
+		return false
+	}
+
+	for _, r := range host {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
 }
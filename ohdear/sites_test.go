@@ -1,6 +1,8 @@
 package ohdear
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -59,3 +61,384 @@ func TestSitesSrv_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestSitesSrv_GetBrokenLinksWhitelist(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, testdata.SingleSiteResponse)
+	})
+
+	got, err := tClient.Sites.GetBrokenLinksWhitelist(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{"https://yoursite.tld/ignored", "https://external.tld/flaky"}, got)
+}
+
+func TestSitesSrv_BrokenLinksWhitelistUpdates(t *testing.T) {
+	cases := []struct {
+		name    string
+		want    string
+		wantErr error
+		call    func() (*Site, error)
+	}{
+		{
+			"add many urls skipping duplicates",
+			`{"broken_links_check_include_external_links":true,"broken_links_whitelisted_urls":["https://yoursite.tld/ignored","https://external.tld/flaky","https://yoursite.tld/new"]}`,
+			nil,
+			func() (*Site, error) {
+				return tClient.Sites.AddManyToBrokenLinkWhitelist(1, "https://yoursite.tld/new", "https://external.tld/flaky", "https://yoursite.tld/new")
+			},
+		},
+		{
+			"remove urls",
+			`{"broken_links_check_include_external_links":true,"broken_links_whitelisted_urls":["https://external.tld/flaky"]}`,
+			nil,
+			func() (*Site, error) {
+				return tClient.Sites.RemoveFromBrokenLinkWhitelist(1, "https://yoursite.tld/ignored")
+			},
+		},
+		{
+			"replace with an empty whitelist",
+			`{"broken_links_check_include_external_links":true,"broken_links_whitelisted_urls":[]}`,
+			nil,
+			func() (*Site, error) {
+				return tClient.Sites.ReplaceBrokenLinksWhitelist(1)
+			},
+		},
+		{
+			"replace rejects relative urls",
+			"",
+			ErrInvalidWhitelistURL,
+			func() (*Site, error) {
+				return tClient.Sites.ReplaceBrokenLinksWhitelist(1, "/relative")
+			},
+		},
+		{
+			"add rejects urls without scheme",
+			"",
+			ErrInvalidWhitelistURL,
+			func() (*Site, error) {
+				return tClient.Sites.AddToBrokenLinkWhitelist(1, "yoursite.tld/page")
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			setEnv()
+			setup()
+			defer func() {
+				tearDown()
+				unsetEnv()
+			}()
+
+			tMux.HandleFunc("/sites/1", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodGet)
+				_, _ = fmt.Fprint(w, testdata.SingleSiteResponse)
+			})
+			tMux.HandleFunc("/sites/1/update-broken-links-settings", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodPut)
+				testBody(t, r, c.want+"\n")
+				_, _ = fmt.Fprint(w, testdata.SingleSiteResponse)
+			})
+
+			got, err := c.call()
+			if c.wantErr != nil {
+				assert.True(t, errors.Is(err, c.wantErr))
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, uint(1), got.ID)
+		})
+	}
+}
+
+func TestSitesSrv_List(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "-sort_url", r.URL.Query().Get("sort"))
+		assert.Equal(t, "http", r.URL.Query().Get("filter[type]"))
+		_, _ = fmt.Fprint(w, testdata.MultipleSitesResponse)
+	})
+
+	got, err := tClient.Sites.List(ListSitesRequestFilters{SortBy: "-sort_url", FilterByType: MonitorHTTP})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, "https://yourothersite.tld", got[1].URL)
+	assert.True(t, got[1].UsesHTTPS)
+	assert.Equal(t, "failed", got[1].SummarizedChecksResult)
+	assert.Len(t, got[1].Checks, 5)
+	assert.Equal(t, BrokenLinksCheck, got[1].Checks[1].Type)
+	assert.Equal(t, CheckFailed, got[1].Checks[1].LatestRunResult)
+	assert.Nil(t, got[1].Checks[4].LatestRunEndedAt)
+}
+
+func TestSitesSrv_CreateWithSettings(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	follow := false
+	settings := SiteSettings{
+		URL:                             "https://yoursite.tld",
+		TeamID:                          1,
+		Checks:                          []CheckType{UptimeCheck, CertificateHealthCheck},
+		UptimeCheckLocation:             "paris",
+		UptimeCheckMethod:               http.MethodPost,
+		UptimeCheckExpectedResponseCode: http.StatusCreated,
+		UptimeCheckHeaders:              []*HTTPField{{Name: "X-Monitor", Value: "ohdear"}},
+		UptimeCheckLookForString:        "Welcome",
+		UptimeCheckTimeoutInSeconds:     5,
+		UptimeCheckFollowRedirects:      &follow,
+	}
+
+	tMux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		testBody(t, r, `{"url":"https://yoursite.tld","team_id":1,"checks":["uptime","certificate_health"],`+
+			`"uptime_check_location":"paris","uptime_check_method":"POST","uptime_check_expected_response_code":201,`+
+			`"uptime_check_headers":[{"name":"X-Monitor","value":"ohdear"}],"uptime_check_look_for_string":"Welcome",`+
+			`"uptime_check_timeout_in_seconds":5,"uptime_check_follow_redirects":false}`+"\n")
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, testdata.SingleSiteResponse)
+	})
+
+	got, err := tClient.Sites.CreateWithSettings(context.Background(), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint(1), got.ID)
+
+	_, err = tClient.Sites.CreateWithSettings(context.Background(), SiteSettings{TeamID: 1})
+	assert.True(t, errors.Is(err, ErrInvalidSiteSettings))
+}
+
+func TestSitesSrv_CreateWithSettingsMonitorTypes(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		testBody(t, r, `{"type":"tcp","host":"db.yoursite.tld","port":5432,"tcp_expectation":"closed","team_id":1}`+"\n")
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"id":3,"type":"tcp","host":"db.yoursite.tld","port":5432,"tcp_expectation":"closed","team_id":1}`)
+	})
+
+	got, err := tClient.Sites.CreateWithSettings(context.Background(), SiteSettings{
+		Type:           MonitorTCP,
+		Host:           "db.yoursite.tld",
+		Port:           5432,
+		TCPExpectation: TCPExpectClosed,
+		TeamID:         1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, MonitorTCP, got.Kind())
+	assert.Equal(t, "db.yoursite.tld:5432", got.Target())
+	assert.Equal(t, TCPExpectClosed, got.TCPExpectation)
+
+	for _, s := range []SiteSettings{
+		{Type: MonitorPing},
+		{Type: MonitorTCP, Host: "db.yoursite.tld"},
+		{Type: MonitorHTTP, Host: "db.yoursite.tld"},
+	} {
+		_, err = tClient.Sites.CreateWithSettings(context.Background(), s)
+		assert.True(t, errors.Is(err, ErrInvalidSiteSettings), "%v", s)
+	}
+}
+
+func TestSite_Target(t *testing.T) {
+	assert.Equal(t, "https://yoursite.tld", (&Site{URL: "https://yoursite.tld"}).Target())
+	assert.Equal(t, MonitorHTTP, (&Site{}).Kind())
+	assert.Equal(t, "yoursite.tld", (&Site{Type: MonitorPing, Host: "yoursite.tld"}).Target())
+	assert.Equal(t, "[2001:db8::1]:22", (&Site{Type: MonitorTCP, Host: "2001:db8::1", Port: 22}).Target())
+}
+
+func TestSitesSrv_Update(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		testBody(t, r, `{"uptime_check_method":"HEAD","uptime_check_absent_string":"Error"}`+"\n")
+
+		_, _ = fmt.Fprint(w, testdata.SingleSiteResponse)
+	})
+
+	got, err := tClient.Sites.Update(context.Background(), 1, SiteSettings{UptimeCheckMethod: "head", UptimeCheckAbsentString: "Error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint(1), got.ID)
+}
+
+func TestSiteSettings_Validate(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings SiteSettings
+		wantErr  bool
+	}{
+		{"empty settings", SiteSettings{}, false},
+		{"lowercase method", SiteSettings{UptimeCheckMethod: "head"}, false},
+		{"relative url", SiteSettings{URL: "yoursite.tld"}, true},
+		{"unsupported method", SiteSettings{UptimeCheckMethod: "TRACE"}, true},
+		{"invalid response code", SiteSettings{UptimeCheckExpectedResponseCode: 42}, true},
+		{"unnamed header", SiteSettings{UptimeCheckHeaders: []*HTTPField{{Value: "x"}}}, true},
+		{"unknown monitor type", SiteSettings{Type: "smtp"}, true},
+		{"ping monitor", SiteSettings{Type: MonitorPing, Host: "yoursite.tld", Checks: []CheckType{UptimeCheck}}, false},
+		{"ping ip address", SiteSettings{Type: MonitorPing, Host: "2001:db8::1"}, false},
+		{"ping with port", SiteSettings{Type: MonitorPing, Host: "yoursite.tld", Port: 22}, true},
+		{"ping with url", SiteSettings{Type: MonitorPing, URL: "https://yoursite.tld"}, true},
+		{"ping with broken links", SiteSettings{Type: MonitorPing, Checks: []CheckType{BrokenLinksCheck}}, true},
+		{"tcp monitor", SiteSettings{Type: MonitorTCP, Host: "db.yoursite.tld", Port: 5432, TCPExpectation: TCPExpectClosed}, false},
+		{"tcp with http method", SiteSettings{Type: MonitorTCP, UptimeCheckMethod: http.MethodGet}, true},
+		{"tcp invalid port", SiteSettings{Type: MonitorTCP, Host: "yoursite.tld", Port: 70000}, true},
+		{"tcp unknown expectation", SiteSettings{Type: MonitorTCP, TCPExpectation: "filtered"}, true},
+		{"host with scheme", SiteSettings{Host: "tcp://yoursite.tld"}, true},
+		{"host with port", SiteSettings{Host: "yoursite.tld:22"}, true},
+		{"http with host", SiteSettings{Type: MonitorHTTP, Host: "yoursite.tld"}, true},
+		{"port update", SiteSettings{Port: 2222}, false},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			err := c.settings.Validate()
+			if c.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidSiteSettings))
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestSitesSrv_GetUptimePercentage(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/uptime", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "20180922000000", r.URL.Query().Get("filter[started_at]"))
+		assert.Equal(t, "day", r.URL.Query().Get("split"))
+		_, _ = fmt.Fprint(w, testdata.UptimeResponse)
+	})
+
+	got, err := tClient.Sites.GetUptimePercentage(1, UptimeRequestFilters{
+		StartedAt: "20180922000000",
+		EndedAt:   "20180924000000",
+		Split:     SplitByDay,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 2)
+	assert.Equal(t, 22, got.Data[0].Datetime.Day())
+	assert.Equal(t, 99.98, got.Data[0].UptimePercentage)
+}
+
+func TestSitesSrv_GetDowntimePeriods(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/sites/1/downtime", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "20180924000000", r.URL.Query().Get("filter[ended_at]"))
+		_, _ = fmt.Fprint(w, testdata.DowntimeResponse)
+	})
+
+	got, err := tClient.Sites.GetDowntimePeriods(1, DowntimeRequestFilters{
+		StartedAt: "20180922000000",
+		EndedAt:   "20180924000000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, got.Data, 2)
+	assert.Equal(t, 5, got.Data[0].EndedAt.Minute())
+	assert.Nil(t, got.Data[1].EndedAt)
+}
+
+func TestSitesSrv_ListAll(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	var pages []string
+	tMux.HandleFunc("/sites", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "10", r.URL.Query().Get("page[size]"))
+		assert.Equal(t, "1", r.URL.Query().Get("filter[team_id]"))
+
+		page := r.URL.Query().Get("page[number]")
+		pages = append(pages, page)
+		if page == "1" {
+			_, _ = fmt.Fprint(w, testdata.SitesFirstPageResponse)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, testdata.SitesLastPageResponse)
+	})
+
+	got, err := tClient.Sites.ListAll(context.Background(), ListSitesRequestFilters{PageSize: 10, FilterByTeamID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{"1", "2"}, pages, "pages smaller than requested are not the last one")
+	assert.Len(t, got, 3)
+	assert.Equal(t, uint(3), got[2].ID)
+}
@@ -0,0 +1,77 @@
+package sla
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Budget describes the downtime allowed by a target and
+// how much of it has been consumed.
+//
+// Durations are encoded as seconds when marshaled to JSON.
+type Budget struct {
+	Allowed   time.Duration
+	Consumed  time.Duration
+	Remaining time.Duration
+	// ConsumedPercentage can go beyond 100 when the target was missed.
+	ConsumedPercentage float64
+}
+
+// Exhausted reports if the downtime exceeded the allowed budget.
+func (b Budget) Exhausted() bool {
+	return b.Consumed > b.Allowed
+}
+
+// MarshalJSON encodes the budget durations as seconds.
+func (b Budget) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Allowed            float64 `json:"allowed_seconds"`
+		Consumed           float64 `json:"consumed_seconds"`
+		Remaining          float64 `json:"remaining_seconds"`
+		ConsumedPercentage float64 `json:"consumed_percentage"`
+	}{
+		b.Allowed.Seconds(),
+		b.Consumed.Seconds(),
+		b.Remaining.Seconds(),
+		b.ConsumedPercentage,
+	})
+}
+
+func newBudget(target float64, observed, down time.Duration) Budget {
+	b := Budget{
+		Allowed:  time.Duration(float64(observed) * (100 - target) / 100),
+		Consumed: down,
+	}
+	b.Remaining = b.Allowed - b.Consumed
+
+	switch {
+	case b.Allowed > 0:
+		b.ConsumedPercentage = 100 * float64(b.Consumed) / float64(b.Allowed)
+	case b.Consumed > 0:
+		b.ConsumedPercentage = 100
+	}
+
+	return b
+}
+
+// MonthlyRollup aggregates the availability of a calendar month.
+type MonthlyRollup struct {
+	Month        time.Time
+	Availability float64
+	Downtime     time.Duration
+
+	observed time.Duration
+}
+
+// MarshalJSON encodes the month as YYYY-MM and the downtime as seconds.
+func (m *MonthlyRollup) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Month        string  `json:"month"`
+		Availability float64 `json:"availability"`
+		Downtime     float64 `json:"downtime_seconds"`
+	}{
+		m.Month.Format("2006-01"),
+		m.Availability,
+		m.Downtime.Seconds(),
+	})
+}
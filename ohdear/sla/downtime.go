@@ -0,0 +1,136 @@
+package sla
+
+import (
+	"sort"
+	"time"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+// DowntimeStats summarizes the downtime periods of a site.
+type DowntimeStats struct {
+	// Window is the period the statistics were computed for.
+	Window Window
+	// Incidents is the amount of outages once overlapping periods are merged.
+	Incidents int
+	// Ongoing reports if the last outage has not ended yet.
+	Ongoing bool
+	Total   time.Duration
+	Longest time.Duration
+	Median  time.Duration
+	// MTTR is the mean time to recovery, the average outage duration.
+	MTTR time.Duration
+	// MTBF is the mean time between failures, the time the site was up
+	// divided by the amount of incidents.
+	MTBF time.Duration
+}
+
+// MergeDowntime converts downtime periods into sorted windows,
+// merging the ones which overlap or touch each other.
+//
+// Periods without an end date are considered ongoing and end at now.
+func MergeDowntime(periods []*ohdear.DowntimePeriods, now time.Time) (merged []Window) {
+	windows := make([]Window, 0, len(periods))
+	for _, p := range periods {
+		if p == nil || p.StartedAt == nil {
+			continue
+		}
+
+		end := now
+		if p.EndedAt != nil {
+			end = p.EndedAt.Time
+		}
+
+		if end.After(p.StartedAt.Time) {
+			windows = append(windows, Window{Start: p.StartedAt.Time, End: end})
+		}
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+
+	for _, w := range windows {
+		if n := len(merged); n > 0 && !w.Start.After(merged[n-1].End) {
+			if w.End.After(merged[n-1].End) {
+				merged[n-1].End = w.End
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+
+	return
+}
+
+// ClipDowntime restricts the windows to the given one,
+// dropping those falling completely outside of it.
+func ClipDowntime(windows []Window, w Window) (clipped []Window) {
+	for _, d := range windows {
+		if c := clip(d.Start, d.End, w); !c.End.IsZero() {
+			clipped = append(clipped, c)
+		}
+	}
+
+	return
+}
+
+// AnalyzeDowntime computes the downtime statistics of the periods
+// falling inside the window.
+//
+// A zero window skips the clipping and spans from the start of the first
+// outage to the end of the last one. Periods without an end date are
+// considered ongoing and end at now.
+func AnalyzeDowntime(periods []*ohdear.DowntimePeriods, w Window, now time.Time) DowntimeStats {
+	windows := MergeDowntime(periods, now)
+	unbounded := w.Start.IsZero() && w.End.IsZero()
+
+	ongoing := false
+	for _, p := range periods {
+		if p != nil && p.StartedAt != nil && p.EndedAt == nil && (unbounded || p.StartedAt.Before(w.End)) {
+			ongoing = true
+		}
+	}
+
+	if unbounded {
+		if len(windows) > 0 {
+			w = Window{Start: windows[0].Start, End: windows[len(windows)-1].End}
+		}
+	} else {
+		windows = ClipDowntime(windows, w)
+	}
+
+	stats := DowntimeStats{
+		Window:    w,
+		Incidents: len(windows),
+		Ongoing:   ongoing,
+	}
+
+	if stats.Incidents == 0 {
+		return stats
+	}
+
+	durations := make([]time.Duration, 0, len(windows))
+	for _, d := range windows {
+		l := d.Duration()
+		durations = append(durations, l)
+		stats.Total += l
+		if l > stats.Longest {
+			stats.Longest = l
+		}
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	if n := len(durations); n%2 == 1 {
+		stats.Median = durations[n/2]
+	} else {
+		stats.Median = (durations[n/2-1] + durations[n/2]) / 2
+	}
+
+	stats.MTTR = stats.Total / time.Duration(stats.Incidents)
+	if up := w.Duration() - stats.Total; up > 0 {
+		stats.MTBF = up / time.Duration(stats.Incidents)
+	}
+
+	return stats
+}
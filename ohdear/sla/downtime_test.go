@@ -0,0 +1,122 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+func at(h, m int) time.Time {
+	return time.Date(2020, time.May, 1, h, m, 0, 0, time.UTC)
+}
+
+func period(start, end time.Time) *ohdear.DowntimePeriods {
+	p := &ohdear.DowntimePeriods{StartedAt: &ohdear.CustomDate{Time: start}}
+	if !end.IsZero() {
+		p.EndedAt = &ohdear.CustomDate{Time: end}
+	}
+
+	return p
+}
+
+func TestMergeDowntime(t *testing.T) {
+	got := MergeDowntime([]*ohdear.DowntimePeriods{
+		period(at(3, 0), at(3, 10)),
+		period(at(1, 0), at(1, 30)),
+		period(at(1, 20), at(1, 40)),
+		period(at(1, 40), at(1, 45)),
+		period(at(5, 0), time.Time{}),
+		nil,
+	}, at(5, 15))
+
+	assert.Equal(t, []Window{
+		{Start: at(1, 0), End: at(1, 45)},
+		{Start: at(3, 0), End: at(3, 10)},
+		{Start: at(5, 0), End: at(5, 15)},
+	}, got)
+}
+
+func TestClipDowntime(t *testing.T) {
+	got := ClipDowntime([]Window{
+		{Start: at(0, 0), End: at(1, 0)},
+		{Start: at(1, 50), End: at(2, 10)},
+		{Start: at(3, 0), End: at(4, 0)},
+	}, Window{Start: at(0, 30), End: at(2, 0)})
+
+	assert.Equal(t, []Window{
+		{Start: at(0, 30), End: at(1, 0)},
+		{Start: at(1, 50), End: at(2, 0)},
+	}, got)
+}
+
+func TestAnalyzeDowntime(t *testing.T) {
+	periods := []*ohdear.DowntimePeriods{
+		period(at(1, 0), at(1, 10)),
+		period(at(1, 5), at(1, 20)),
+		period(at(4, 0), at(4, 5)),
+		period(at(8, 0), time.Time{}),
+	}
+
+	cases := []struct {
+		name   string
+		window Window
+		want   DowntimeStats
+	}{
+		{
+			"whole day with an ongoing outage",
+			Window{Start: at(0, 0), End: at(10, 0)},
+			DowntimeStats{
+				Window:    Window{Start: at(0, 0), End: at(10, 0)},
+				Incidents: 3,
+				Ongoing:   true,
+				Total:     85 * time.Minute,
+				Longest:   time.Hour,
+				Median:    20 * time.Minute,
+				MTTR:      85 * time.Minute / 3,
+				MTBF:      (10*time.Hour - 85*time.Minute) / 3,
+			},
+		},
+		{
+			"window clipping the first outage",
+			Window{Start: at(1, 10), End: at(5, 0)},
+			DowntimeStats{
+				Window:    Window{Start: at(1, 10), End: at(5, 0)},
+				Incidents: 2,
+				Total:     15 * time.Minute,
+				Longest:   10 * time.Minute,
+				Median:    7*time.Minute + 30*time.Second,
+				MTTR:      7*time.Minute + 30*time.Second,
+				MTBF:      (3*time.Hour + 35*time.Minute) / 2,
+			},
+		},
+		{
+			"window without outages",
+			Window{Start: at(2, 0), End: at(3, 0)},
+			DowntimeStats{Window: Window{Start: at(2, 0), End: at(3, 0)}},
+		},
+		{
+			"unbounded window",
+			Window{},
+			DowntimeStats{
+				Window:    Window{Start: at(1, 0), End: at(9, 0)},
+				Incidents: 3,
+				Ongoing:   true,
+				Total:     85 * time.Minute,
+				Longest:   time.Hour,
+				Median:    20 * time.Minute,
+				MTTR:      85 * time.Minute / 3,
+				MTBF:      (8*time.Hour - 85*time.Minute) / 3,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, AnalyzeDowntime(periods, c.window, at(9, 0)))
+		})
+	}
+}
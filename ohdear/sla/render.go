@@ -0,0 +1,88 @@
+package sla
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// WriteJSON encodes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// WriteCSV writes one row per site followed by the fleet totals,
+// the values of sites without data are left empty.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"site_id",
+		"url",
+		"availability",
+		"allowed_downtime_seconds",
+		"consumed_downtime_seconds",
+		"remaining_downtime_seconds",
+		"budget_consumed_percentage",
+	})
+
+	row := func(id, url string, availability float64, b Budget) {
+		_ = cw.Write([]string{
+			id,
+			url,
+			formatFloat(availability),
+			formatFloat(b.Allowed.Seconds()),
+			formatFloat(b.Consumed.Seconds()),
+			formatFloat(b.Remaining.Seconds()),
+			formatFloat(b.ConsumedPercentage),
+		})
+	}
+
+	for _, s := range r.Sites {
+		id := strconv.FormatUint(uint64(s.SiteID), 10)
+		if s.NoData {
+			_ = cw.Write([]string{id, s.URL, "", "", "", "", ""})
+			continue
+		}
+		row(id, s.URL, s.Availability, s.ErrorBudget)
+	}
+	row("total", "", r.Availability, r.ErrorBudget)
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteMonthlyCSV writes one row per site and month followed by
+// the fleet monthly rollups.
+func (r *Report) WriteMonthlyCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"site_id", "month", "availability", "downtime_seconds"})
+
+	rows := func(id string, months []*MonthlyRollup) {
+		for _, m := range months {
+			_ = cw.Write([]string{
+				id,
+				m.Month.Format("2006-01"),
+				formatFloat(m.Availability),
+				formatFloat(m.Downtime.Seconds()),
+			})
+		}
+	}
+
+	for _, s := range r.Sites {
+		rows(strconv.FormatUint(uint64(s.SiteID), 10), s.Months)
+	}
+	rows("total", r.Months)
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,329 @@
+// Package sla computes availability and error budgets for a fleet of
+// oh-dear sites based on their uptime percentages.
+//
+// The uptime of every site is fetched concurrently using
+// SitesSrv.GetUptimePercentage, each returned bucket is weighted by the
+// portion of its duration which falls inside the requested window.
+//
+// Downtime periods returned by SitesSrv.GetDowntimePeriods can be
+// analyzed with AnalyzeDowntime to obtain incident counts, outage
+// durations, MTTR and MTBF.
+package sla
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+// DefaultConcurrency is the amount of sites fetched in parallel
+// when the calculator does not define one.
+const DefaultConcurrency int = 4
+
+// Package level errors
+var (
+	ErrInvalidWindow error = fmt.Errorf("the window end must be after its start")
+	ErrInvalidTarget error = fmt.Errorf("the target must be a percentage between 0 and 100")
+)
+
+// UptimeGetter retrieves the uptime percentages of a site,
+// it is implemented by ohdear.SitesSrv.
+type UptimeGetter interface {
+	GetUptimePercentage(id uint, filters ohdear.UptimeRequestFilters) (*ohdear.UptimeResponse, error)
+}
+
+// Window is the period of time covered by a report.
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Duration returns the length of the window.
+func (w Window) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// Calculator computes SLA reports for a set of sites.
+type Calculator struct {
+	Uptime UptimeGetter
+	// Target is the availability objective as a percentage, e.g. 99.9.
+	Target float64
+	// Split controls the granularity of the uptime buckets,
+	// defaults to ohdear.SplitByHour.
+	Split ohdear.SplitValue
+	// Concurrency limits the amount of parallel requests,
+	// defaults to DefaultConcurrency.
+	Concurrency int
+}
+
+// NewCalculator returns a calculator using the sites service of the
+// given client and the provided availability target.
+func NewCalculator(c *ohdear.Client, target float64) *Calculator {
+	return &Calculator{
+		Uptime: c.Sites,
+		Target: target,
+	}
+}
+
+// Report describes the availability of a fleet of sites in a window.
+type Report struct {
+	Window       Window           `json:"window"`
+	Target       float64          `json:"target"`
+	Availability float64          `json:"availability"`
+	ErrorBudget  Budget           `json:"error_budget"`
+	Sites        []*SiteReport    `json:"sites"`
+	Months       []*MonthlyRollup `json:"months"`
+}
+
+// SiteReport describes the availability of a single site in a window.
+//
+// NoData is set when no uptime was observed inside the window, the
+// availability and error budget of such sites are left empty and
+// they do not count towards the fleet totals.
+type SiteReport struct {
+	SiteID       uint             `json:"site_id"`
+	URL          string           `json:"url"`
+	NoData       bool             `json:"no_data"`
+	Availability float64          `json:"availability"`
+	ErrorBudget  Budget           `json:"error_budget"`
+	Months       []*MonthlyRollup `json:"months"`
+}
+
+// Compute fetches the uptime of every site inside the window and
+// builds the fleet report.
+//
+// The fleet availability is the duration weighted average of the
+// availability of each site.
+func (c *Calculator) Compute(ctx context.Context, sites []*ohdear.Site, w Window) (*Report, error) {
+	if !w.End.After(w.Start) {
+		return nil, ErrInvalidWindow
+	}
+
+	if c.Target <= 0 || c.Target > 100 {
+		return nil, ErrInvalidTarget
+	}
+
+	split := c.Split
+	if split == "" {
+		split = ohdear.SplitByHour
+	}
+
+	workers := c.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, workers)
+		reports  = make([]*SiteReport, len(sites))
+	)
+
+	for i, s := range sites {
+		wg.Add(1)
+		go func(i int, s *ohdear.Site) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			ur, err := c.Uptime.GetUptimePercentage(s.ID, ohdear.UptimeRequestFilters{
+				StartedAt: ohdear.FilterDate(w.Start),
+				EndedAt:   ohdear.FilterDate(w.End),
+				Split:     split,
+			})
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("site %d: %w", s.ID, err)
+					cancel()
+				})
+				return
+			}
+
+			reports[i] = c.siteReport(s, ur, split, w)
+		}(i, s)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.fleetReport(reports, w), nil
+}
+
+func (c *Calculator) siteReport(s *ohdear.Site, ur *ohdear.UptimeResponse, split ohdear.SplitValue, w Window) *SiteReport {
+	var data []*ohdear.UptimePerDatetime
+	if ur != nil {
+		data = ur.Data
+	}
+
+	months := make(map[time.Time]*MonthlyRollup)
+	var observed, down time.Duration
+	for _, b := range data {
+		if b == nil || b.Datetime == nil {
+			continue
+		}
+
+		for _, p := range splitByMonth(clip(b.Datetime.Time, bucketEnd(b.Datetime.Time, split), w)) {
+			d := p.End.Sub(p.Start)
+			lost := time.Duration(float64(d) * (100 - b.UptimePercentage) / 100)
+			observed += d
+			down += lost
+
+			m := monthOf(p.Start)
+			r, ok := months[m]
+			if !ok {
+				r = &MonthlyRollup{Month: m}
+				months[m] = r
+			}
+			r.observed += d
+			r.Downtime += lost
+		}
+	}
+
+	sr := &SiteReport{
+		SiteID: s.ID,
+		URL:    s.URL,
+	}
+
+	if observed <= 0 {
+		sr.NoData = true
+		return sr
+	}
+
+	sr.Availability = availability(observed, down)
+	sr.ErrorBudget = newBudget(c.Target, observed, down)
+
+	for _, m := range months {
+		m.Availability = availability(m.observed, m.Downtime)
+		sr.Months = append(sr.Months, m)
+	}
+	sortMonths(sr.Months)
+
+	return sr
+}
+
+func (c *Calculator) fleetReport(sites []*SiteReport, w Window) *Report {
+	r := &Report{
+		Window: w,
+		Target: c.Target,
+		Sites:  sites,
+	}
+
+	months := make(map[time.Time]*MonthlyRollup)
+	var observed, down time.Duration
+	for _, s := range sites {
+		for _, m := range s.Months {
+			observed += m.observed
+			down += m.Downtime
+
+			f, ok := months[m.Month]
+			if !ok {
+				f = &MonthlyRollup{Month: m.Month}
+				months[m.Month] = f
+			}
+			f.observed += m.observed
+			f.Downtime += m.Downtime
+		}
+	}
+
+	r.Availability = availability(observed, down)
+	r.ErrorBudget = newBudget(c.Target, observed, down)
+	for _, m := range months {
+		m.Availability = availability(m.observed, m.Downtime)
+		r.Months = append(r.Months, m)
+	}
+	sortMonths(r.Months)
+
+	return r
+}
+
+func availability(observed, down time.Duration) float64 {
+	if observed <= 0 {
+		return 0
+	}
+
+	return 100 * float64(observed-down) / float64(observed)
+}
+
+// bucketEnd returns the end of the bucket starting at t.
+func bucketEnd(t time.Time, split ohdear.SplitValue) time.Time {
+	switch split {
+	case ohdear.SplitByDay:
+		return t.AddDate(0, 0, 1)
+	case ohdear.SplitByMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.Add(time.Hour)
+	}
+}
+
+// clip restricts the [start, end) interval to the window, the returned
+// window is empty when both do not overlap.
+func clip(start, end time.Time, w Window) Window {
+	if start.Before(w.Start) {
+		start = w.Start
+	}
+
+	if end.After(w.End) {
+		end = w.End
+	}
+
+	if !end.After(start) {
+		return Window{}
+	}
+
+	return Window{Start: start, End: end}
+}
+
+// splitByMonth breaks a window at month boundaries.
+func splitByMonth(w Window) (parts []Window) {
+	for start := w.Start; w.End.After(start); {
+		end := monthOf(start).AddDate(0, 1, 0)
+		if end.After(w.End) {
+			end = w.End
+		}
+		parts = append(parts, Window{Start: start, End: end})
+		start = end
+	}
+
+	return
+}
+
+// monthOf returns the start of the UTC calendar month containing t,
+// months are always computed in UTC so rollups of windows expressed
+// in other locations share the same keys.
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func sortMonths(months []*MonthlyRollup) {
+	sort.Slice(months, func(i, j int) bool {
+		return months[i].Month.Before(months[j].Month)
+	})
+}
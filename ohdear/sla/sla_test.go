@@ -0,0 +1,201 @@
+package sla
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+type fakeUptime struct {
+	mu      sync.Mutex
+	data    map[uint][]*ohdear.UptimePerDatetime
+	err     error
+	filters []ohdear.UptimeRequestFilters
+}
+
+func (f *fakeUptime) GetUptimePercentage(id uint, filters ohdear.UptimeRequestFilters) (*ohdear.UptimeResponse, error) {
+	f.mu.Lock()
+	f.filters = append(f.filters, filters)
+	f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &ohdear.UptimeResponse{Data: f.data[id]}, nil
+}
+
+func bucket(t time.Time, pct float64) *ohdear.UptimePerDatetime {
+	return &ohdear.UptimePerDatetime{Datetime: &ohdear.CustomDate{Time: t}, UptimePercentage: pct}
+}
+
+func day(m time.Month, d int) time.Time {
+	return time.Date(2020, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCalculator_Compute(t *testing.T) {
+	uptime := &fakeUptime{data: map[uint][]*ohdear.UptimePerDatetime{
+		// 50% on the last day of january, 100% on the first of february.
+		1: {bucket(day(time.January, 31), 50), bucket(day(time.February, 1), 100)},
+		// Full availability, with a bucket outside the window which is ignored.
+		2: {bucket(day(time.January, 31), 100), bucket(day(time.February, 1), 100), bucket(day(time.February, 2), 0)},
+	}}
+
+	c := &Calculator{Uptime: uptime, Target: 99, Split: ohdear.SplitByDay, Concurrency: 1}
+	w := Window{Start: day(time.January, 31), End: day(time.February, 2)}
+	sites := []*ohdear.Site{{ID: 1, URL: "https://one.tld"}, {ID: 2, URL: "https://two.tld"}}
+
+	got, err := c.Compute(context.Background(), sites, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, uptime.filters, 2)
+	assert.Equal(t, "20200131000000", uptime.filters[0].StartedAt)
+	assert.Equal(t, "20200202000000", uptime.filters[0].EndedAt)
+	assert.Equal(t, ohdear.SplitByDay, uptime.filters[0].Split)
+
+	one := got.Sites[0]
+	assert.Equal(t, uint(1), one.SiteID)
+	assert.Equal(t, 75.0, one.Availability)
+	assert.Equal(t, 12*time.Hour, one.ErrorBudget.Consumed)
+	assert.Equal(t, 28*time.Minute+48*time.Second, one.ErrorBudget.Allowed)
+	assert.True(t, one.ErrorBudget.Exhausted())
+	assert.Len(t, one.Months, 2)
+	assert.Equal(t, time.January, one.Months[0].Month.Month())
+	assert.Equal(t, 50.0, one.Months[0].Availability)
+	assert.Equal(t, 100.0, one.Months[1].Availability)
+
+	assert.Equal(t, 100.0, got.Sites[1].Availability)
+	assert.False(t, got.Sites[1].ErrorBudget.Exhausted())
+
+	assert.Equal(t, 87.5, got.Availability)
+	assert.Equal(t, 75.0, got.Months[0].Availability)
+	assert.Equal(t, 12*time.Hour, got.ErrorBudget.Consumed)
+}
+
+func TestCalculator_ComputeClipsHourlyBuckets(t *testing.T) {
+	start := time.Date(2020, time.March, 1, 10, 30, 0, 0, time.UTC)
+	uptime := &fakeUptime{data: map[uint][]*ohdear.UptimePerDatetime{
+		1: {bucket(start.Truncate(time.Hour), 0), bucket(start.Truncate(time.Hour).Add(time.Hour), 100)},
+	}}
+
+	c := &Calculator{Uptime: uptime, Target: 99.9}
+	got, err := c.Compute(context.Background(), []*ohdear.Site{{ID: 1}}, Window{Start: start, End: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, ohdear.SplitByHour, uptime.filters[0].Split)
+	assert.Equal(t, 50.0, got.Availability)
+	assert.Equal(t, 30*time.Minute, got.ErrorBudget.Consumed)
+}
+
+func TestCalculator_ComputeNonUTCWindow(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	uptime := &fakeUptime{data: map[uint][]*ohdear.UptimePerDatetime{
+		1: {bucket(day(time.January, 31), 50), bucket(day(time.February, 1), 100)},
+	}}
+
+	c := &Calculator{Uptime: uptime, Target: 99, Split: ohdear.SplitByDay}
+	w := Window{Start: day(time.January, 31).In(loc), End: day(time.February, 2).In(loc)}
+	got, err := c.Compute(context.Background(), []*ohdear.Site{{ID: 1}}, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	months := got.Sites[0].Months
+	assert.Len(t, months, 2)
+	assert.Equal(t, day(time.January, 1), months[0].Month)
+	assert.Equal(t, 50.0, months[0].Availability)
+	assert.Equal(t, day(time.February, 1), months[1].Month)
+	assert.Equal(t, 100.0, months[1].Availability)
+	assert.Len(t, got.Months, 2)
+}
+
+func TestCalculator_ComputeSitesWithoutData(t *testing.T) {
+	uptime := &fakeUptime{data: map[uint][]*ohdear.UptimePerDatetime{
+		1: {bucket(day(time.January, 1), 99.5)},
+	}}
+
+	c := &Calculator{Uptime: uptime, Target: 99, Split: ohdear.SplitByDay}
+	sites := []*ohdear.Site{{ID: 1, URL: "https://one.tld"}, {ID: 2, URL: "https://two.tld"}}
+	got, err := c.Compute(context.Background(), sites, Window{Start: day(time.January, 1), End: day(time.January, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	empty := got.Sites[1]
+	assert.True(t, empty.NoData)
+	assert.Equal(t, Budget{}, empty.ErrorBudget)
+	assert.Empty(t, empty.Months)
+	assert.False(t, got.Sites[0].NoData)
+
+	assert.Equal(t, 99.5, got.Availability)
+	assert.Equal(t, 864*time.Second, got.ErrorBudget.Allowed)
+
+	var buf bytes.Buffer
+	assert.Nil(t, got.WriteCSV(&buf))
+	assert.Contains(t, buf.String(), "\n2,https://two.tld,,,,,\n")
+	assert.Contains(t, buf.String(), "\ntotal,,99.5,864,432,432,50\n")
+}
+
+func TestCalculator_ComputeErrors(t *testing.T) {
+	w := Window{Start: day(time.January, 1), End: day(time.January, 2)}
+	sites := []*ohdear.Site{{ID: 1}, {ID: 2}}
+
+	_, err := (&Calculator{Uptime: &fakeUptime{}, Target: 99}).Compute(context.Background(), sites, Window{})
+	assert.Equal(t, ErrInvalidWindow, err)
+
+	_, err = (&Calculator{Uptime: &fakeUptime{}, Target: 101}).Compute(context.Background(), sites, w)
+	assert.Equal(t, ErrInvalidTarget, err)
+
+	boom := errors.New("boom")
+	_, err = (&Calculator{Uptime: &fakeUptime{err: boom}, Target: 99}).Compute(context.Background(), sites, w)
+	assert.True(t, errors.Is(err, boom))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = (&Calculator{Uptime: &fakeUptime{}, Target: 99}).Compute(ctx, sites, w)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestReport_Render(t *testing.T) {
+	uptime := &fakeUptime{data: map[uint][]*ohdear.UptimePerDatetime{
+		1: {bucket(day(time.January, 1), 99.5)},
+	}}
+
+	c := &Calculator{Uptime: uptime, Target: 99, Split: ohdear.SplitByDay}
+	r, err := c.Compute(context.Background(), []*ohdear.Site{{ID: 1, URL: "https://one.tld"}}, Window{Start: day(time.January, 1), End: day(time.January, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, r.WriteCSV(&buf))
+	assert.Equal(t, strings.Join([]string{
+		"site_id,url,availability,allowed_downtime_seconds,consumed_downtime_seconds,remaining_downtime_seconds,budget_consumed_percentage",
+		"1,https://one.tld,99.5,864,432,432,50",
+		"total,,99.5,864,432,432,50",
+		"",
+	}, "\n"), buf.String())
+
+	buf.Reset()
+	assert.Nil(t, r.WriteMonthlyCSV(&buf))
+	assert.Equal(t, "site_id,month,availability,downtime_seconds\n1,2020-01,99.5,432\ntotal,2020-01,99.5,432\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, r.WriteJSON(&buf))
+	assert.Contains(t, buf.String(), `"allowed_seconds": 864`)
+	assert.Contains(t, buf.String(), `"month": "2020-01"`)
+	assert.Contains(t, buf.String(), fmt.Sprintf(`"start": %q`, day(time.January, 1).Format(time.RFC3339)))
+}
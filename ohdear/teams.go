@@ -0,0 +1,96 @@
+package ohdear
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MePath is the resource path describing the authenticated user.
+const MePath string = "me"
+
+// TeamsSrv operates over the teams of the authenticated user.
+type TeamsSrv srv
+
+// User is the owner of the API token.
+type User struct {
+	ID       uint    `json:"id,omitempty"`
+	Name     string  `json:"name,omitempty"`
+	Email    string  `json:"email,omitempty"`
+	PhotoURL string  `json:"photo_url,omitempty"`
+	Teams    []*Team `json:"teams,omitempty"`
+}
+
+// Team is a team the user belongs to, Role is the role
+// of the user inside the team.
+type Team struct {
+	ID   uint   `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Role string `json:"role,omitempty"`
+}
+
+// Me returns the user owning the API token and its teams,
+// it is a lightweight way to validate a token.
+//
+// See: https://ohdear.app/docs/integrations/the-oh-dear-api#me
+func (c *Client) Me() (u *User, err error) {
+	req, err := c.NewAPIRequest(http.MethodGet, MePath, nil)
+	if err != nil {
+		return
+	}
+
+	res, err := c.Do(req)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(res.content, &u); err != nil {
+		return
+	}
+
+	return
+}
+
+// List returns the teams of the authenticated user.
+func (ts *TeamsSrv) List() (teams []*Team, err error) {
+	u, err := ts.client.Me()
+	if err != nil {
+		return
+	}
+
+	return u.Teams, nil
+}
+
+// ResolveID returns the id of the team with the given name,
+// names are compared without case and surrounding spaces.
+//
+// ErrTeamNotFound is returned when no team, or more
+// than one team, has the given name.
+func (ts *TeamsSrv) ResolveID(name string) (id uint, err error) {
+	teams, err := ts.List()
+	if err != nil {
+		return
+	}
+
+	return FindTeamID(teams, name)
+}
+
+// FindTeamID returns the id of the only team with the given name.
+func FindTeamID(teams []*Team, name string) (uint, error) {
+	var found []*Team
+	for _, t := range teams {
+		if t != nil && strings.EqualFold(strings.TrimSpace(t.Name), strings.TrimSpace(name)) {
+			found = append(found, t)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return 0, fmt.Errorf("%w: %q", ErrTeamNotFound, name)
+	case 1:
+		return found[0].ID, nil
+	}
+
+	return 0, fmt.Errorf("%w: %q matches %d teams", ErrTeamNotFound, name, len(found))
+}
@@ -0,0 +1,70 @@
+package ohdear
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+func TestClient_Me(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		testHeader(t, r, AuthHeader, fmt.Sprintf("%s %s", TokenType, testTkn))
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.MeResponse)
+	})
+
+	got, err := tClient.Me()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "jane@yoursite.tld", got.Email)
+	assert.Len(t, got.Teams, 2)
+	assert.Equal(t, &Team{ID: 9, Name: "Globex Ops", Role: "member"}, got.Teams[1])
+}
+
+func TestTeamsSrv_ResolveID(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	tMux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, testdata.MeResponse)
+	})
+
+	id, err := tClient.Teams.ResolveID(" globex ops")
+	assert.Nil(t, err)
+	assert.Equal(t, uint(9), id)
+
+	_, err = tClient.Teams.ResolveID("Initech")
+	assert.True(t, errors.Is(err, ErrTeamNotFound))
+}
+
+func TestFindTeamID(t *testing.T) {
+	teams := []*Team{{ID: 1, Name: "Acme"}, {ID: 2, Name: "ACME"}, nil, {ID: 3, Name: "Globex"}}
+
+	id, err := FindTeamID(teams, "globex")
+	assert.Nil(t, err)
+	assert.Equal(t, uint(3), id)
+
+	_, err = FindTeamID(teams, "acme")
+	assert.True(t, errors.Is(err, ErrTeamNotFound))
+	assert.Contains(t, err.Error(), "matches 2 teams")
+}
@@ -0,0 +1,205 @@
+package ohdear
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource provides the API token used to authenticate requests,
+// it is consulted every time a new request is built.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// TokenInvalidator is implemented by token sources which keep a
+// token around, the client invalidates the source after receiving
+// an unauthorized response so the next request fetches a fresh token.
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+// TokenSourceFunc adapts a function into a TokenSource.
+type TokenSourceFunc func() (string, error)
+
+// Token calls the wrapped function.
+func (f TokenSourceFunc) Token() (string, error) {
+	return f()
+}
+
+// StaticTokenSource returns a source which always provides the same token.
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func() (string, error) {
+		if token == "" {
+			return "", ErrEmptyAPIToken
+		}
+
+		return token, nil
+	})
+}
+
+// EnvTokenSource returns a source reading the token from the given
+// environment variable on every call.
+func EnvTokenSource(name string) TokenSource {
+	return TokenSourceFunc(func() (string, error) {
+		tkn := strings.TrimSpace(os.Getenv(name))
+		if tkn == "" {
+			return "", fmt.Errorf("%w: %s is not set", ErrEmptyAPIToken, name)
+		}
+
+		return tkn, nil
+	})
+}
+
+// FileTokenSource reads the token from a file, the file is read again
+// only when its size or modification time change.
+type FileTokenSource struct {
+	path    string
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileTokenSource returns a source reading the token stored in path.
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path}
+}
+
+// Token returns the content of the file without surrounding spaces.
+func (fs *FileTokenSource) Token() (string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	info, err := os.Stat(fs.path)
+	if err != nil {
+		return "", err
+	}
+
+	if fs.token != "" && info.ModTime().Equal(fs.modTime) && info.Size() == fs.size {
+		return fs.token, nil
+	}
+
+	b, err := ioutil.ReadFile(fs.path)
+	if err != nil {
+		return "", err
+	}
+
+	tkn := strings.TrimSpace(string(b))
+	if tkn == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrEmptyAPIToken, fs.path)
+	}
+
+	fs.token, fs.modTime, fs.size = tkn, info.ModTime(), info.Size()
+
+	return tkn, nil
+}
+
+// Invalidate forces the file to be read on the next call.
+func (fs *FileTokenSource) Invalidate() {
+	fs.mu.Lock()
+	fs.token = ""
+	fs.mu.Unlock()
+}
+
+// DefaultCommandTokenTimeout bounds the time a token command can run
+// when no timeout is provided, so a hung credential helper does not
+// block every request.
+const DefaultCommandTokenTimeout time.Duration = 30 * time.Second
+
+// CommandTokenSource returns a source running an external command,
+// e.g. `pass show ohdear` or `op read op://vault/ohdear/token`, and
+// using its trimmed standard output as token.
+//
+// The command runs on every call, wrap it with NewCachedTokenSource
+// to avoid running it for every request. It is killed when it runs
+// for longer than DefaultCommandTokenTimeout.
+func CommandTokenSource(name string, args ...string) TokenSource {
+	return CommandTokenSourceWithTimeout(DefaultCommandTokenTimeout, name, args...)
+}
+
+// CommandTokenSourceWithTimeout is like CommandTokenSource but kills
+// the command after the given timeout, a timeout lower or equal to
+// zero uses DefaultCommandTokenTimeout.
+func CommandTokenSourceWithTimeout(timeout time.Duration, name string, args ...string) TokenSource {
+	if timeout <= 0 {
+		timeout = DefaultCommandTokenTimeout
+	}
+
+	return TokenSourceFunc(func() (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Stderr = &stderr
+
+		out, err := cmd.Output()
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("token command %s timed out after %s", name, timeout)
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("token command %s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+
+		tkn := strings.TrimSpace(string(out))
+		if tkn == "" {
+			return "", fmt.Errorf("%w: token command %s printed nothing", ErrEmptyAPIToken, name)
+		}
+
+		return tkn, nil
+	})
+}
+
+// CachedTokenSource keeps the token of another source for a period of time.
+type CachedTokenSource struct {
+	src     TokenSource
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewCachedTokenSource caches the tokens provided by src during ttl,
+// a ttl lower or equal to zero keeps the token until it is invalidated.
+func NewCachedTokenSource(src TokenSource, ttl time.Duration) *CachedTokenSource {
+	return &CachedTokenSource{src: src, ttl: ttl, now: time.Now}
+}
+
+// Token returns the cached token or fetches a new one from the wrapped source.
+func (cs *CachedTokenSource) Token() (string, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.token != "" && (cs.ttl <= 0 || cs.now().Before(cs.expires)) {
+		return cs.token, nil
+	}
+
+	tkn, err := cs.src.Token()
+	if err != nil {
+		return "", err
+	}
+
+	cs.token, cs.expires = tkn, cs.now().Add(cs.ttl)
+
+	return tkn, nil
+}
+
+// Invalidate drops the cached token and invalidates the wrapped source.
+func (cs *CachedTokenSource) Invalidate() {
+	cs.mu.Lock()
+	cs.token = ""
+	cs.mu.Unlock()
+
+	if inv, ok := cs.src.(TokenInvalidator); ok {
+		inv.Invalidate()
+	}
+}
@@ -0,0 +1,186 @@
+package ohdear
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticTokenSource(t *testing.T) {
+	tkn, err := StaticTokenSource("static").Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "static", tkn)
+
+	_, err = StaticTokenSource("").Token()
+	assert.True(t, errors.Is(err, ErrEmptyAPIToken))
+}
+
+func TestEnvTokenSource(t *testing.T) {
+	ts := EnvTokenSource("OHDEAR_TEST_ROTATED_TOKEN")
+
+	_, err := ts.Token()
+	assert.True(t, errors.Is(err, ErrEmptyAPIToken))
+
+	os.Setenv("OHDEAR_TEST_ROTATED_TOKEN", "first")
+	defer os.Unsetenv("OHDEAR_TEST_ROTATED_TOKEN")
+
+	tkn, err := ts.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "first", tkn)
+
+	os.Setenv("OHDEAR_TEST_ROTATED_TOKEN", "second")
+	tkn, _ = ts.Token()
+	assert.Equal(t, "second", tkn)
+}
+
+func TestFileTokenSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ohdear")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := NewFileTokenSource(path)
+	tkn, err := ts.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "first", tkn)
+
+	if err := ioutil.WriteFile(path, []byte("rotated\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tkn, err = ts.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "rotated", tkn)
+
+	if err := ioutil.WriteFile(path, []byte("  \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ts.Token()
+	assert.True(t, errors.Is(err, ErrEmptyAPIToken))
+
+	_, err = NewFileTokenSource(filepath.Join(dir, "missing")).Token()
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCommandTokenSource(t *testing.T) {
+	tkn, err := CommandTokenSource("echo", "from_command").Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "from_command", tkn)
+
+	_, err = CommandTokenSource("false").Token()
+	assert.NotNil(t, err)
+
+	_, err = CommandTokenSource("true").Token()
+	assert.True(t, errors.Is(err, ErrEmptyAPIToken))
+
+	tkn, err = CommandTokenSourceWithTimeout(0, "echo", "default_timeout").Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "default_timeout", tkn)
+
+	start := time.Now()
+	_, err = CommandTokenSourceWithTimeout(50*time.Millisecond, "sleep", "5").Token()
+	assert.EqualError(t, err, "token command sleep timed out after 50ms")
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestCachedTokenSource(t *testing.T) {
+	calls := 0
+	ts := NewCachedTokenSource(TokenSourceFunc(func() (string, error) {
+		calls++
+		return fmt.Sprintf("token_%d", calls), nil
+	}), time.Minute)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts.now = func() time.Time { return now }
+
+	tkn, _ := ts.Token()
+	assert.Equal(t, "token_1", tkn)
+	tkn, _ = ts.Token()
+	assert.Equal(t, "token_1", tkn)
+
+	now = now.Add(time.Minute)
+	tkn, _ = ts.Token()
+	assert.Equal(t, "token_2", tkn)
+
+	ts.Invalidate()
+	tkn, _ = ts.Token()
+	assert.Equal(t, "token_3", tkn)
+}
+
+func TestCachedTokenSource_NoTTL(t *testing.T) {
+	calls := 0
+	ts := NewCachedTokenSource(TokenSourceFunc(func() (string, error) {
+		calls++
+		return "cached", nil
+	}), 0)
+
+	for i := 0; i < 3; i++ {
+		_, _ = ts.Token()
+	}
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_TokenSourceRotation(t *testing.T) {
+	setEnv()
+	setup()
+	defer func() {
+		tearDown()
+		unsetEnv()
+	}()
+
+	calls := 0
+	ts := NewCachedTokenSource(TokenSourceFunc(func() (string, error) {
+		calls++
+		return fmt.Sprintf("token_%d", calls), nil
+	}), 0)
+
+	tClient.tokens = ts
+
+	tMux.HandleFunc("/sites/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(AuthHeader) != "Bearer token_2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Unauthenticated."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+
+	_, err := tClient.Sites.Get(1)
+	assert.NotNil(t, err)
+
+	site, err := tClient.Sites.Get(1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), site.ID)
+	assert.Equal(t, 2, calls)
+}
+
+func TestNewClientWithTokenSource(t *testing.T) {
+	_, err := NewClientWithTokenSource(nil, "", nil)
+	assert.EqualError(t, err, ErrEmptyAPIToken.Error())
+
+	c, err := NewClientWithTokenSource(nil, "", StaticTokenSource("static"))
+	assert.Nil(t, err)
+
+	req, err := c.NewAPIRequest(http.MethodGet, "sites", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer static", req.Header.Get(AuthHeader))
+
+	c, _ = NewClientWithTokenSource(nil, "", EnvTokenSource("OHDEAR_TEST_MISSING_TOKEN"))
+	_, err = c.NewAPIRequest(http.MethodGet, "sites", nil)
+	assert.True(t, errors.Is(err, ErrEmptyAPIToken))
+}
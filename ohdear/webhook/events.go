@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+)
+
+// EventType identifies the notification sent by oh-dear.
+type EventType string
+
+// Supported EventType values.
+const (
+	UptimeCheckFailed      EventType = "uptimeCheckFailed"
+	UptimeCheckRecovered   EventType = "uptimeCheckRecovered"
+	BrokenLinksFound       EventType = "brokenLinksFound"
+	BrokenLinksFixed       EventType = "brokenLinksFixed"
+	MixedContentFound      EventType = "mixedContentFound"
+	MixedContentFixed      EventType = "mixedContentFixed"
+	CertificateExpiresSoon EventType = "certificateExpiresSoon"
+	CertificateHasChanged  EventType = "certificateHasChanged"
+	CertificateUnhealthy   EventType = "certificateUnhealthy"
+	CertificateFixed       EventType = "certificateFixed"
+)
+
+// Event is implemented by all the typed events.
+type Event interface {
+	Meta() *Payload
+}
+
+// Payload contains the values shared by every webhook call.
+type Payload struct {
+	Type EventType `json:"type"`
+	// DateTime is the moment the event was triggered, it uses
+	// the ohdear.FilterDateLayout layout in UTC.
+	DateTime string            `json:"dateTime"`
+	Site     *ohdear.Site      `json:"site,omitempty"`
+	Check    *ohdear.SiteCheck `json:"check,omitempty"`
+	Run      *Run              `json:"run,omitempty"`
+	// Raw is the complete body of the webhook call.
+	Raw json.RawMessage `json:"-"`
+}
+
+// Meta returns the shared values of the event.
+func (p *Payload) Meta() *Payload {
+	return p
+}
+
+// Time parses the moment the event was triggered.
+func (p *Payload) Time() (time.Time, error) {
+	return time.Parse(ohdear.FilterDateLayout, p.DateTime)
+}
+
+// Run describes the check run which triggered the event.
+type Run struct {
+	ID        uint               `json:"id,omitempty"`
+	Result    ohdear.CheckResult `json:"result,omitempty"`
+	Summary   string             `json:"summary,omitempty"`
+	StartedAt *ohdear.CustomDate `json:"started_at,omitempty"`
+	EndedAt   *ohdear.CustomDate `json:"ended_at,omitempty"`
+}
+
+// UptimeCheckFailedEvent is sent when a site goes down.
+type UptimeCheckFailedEvent struct {
+	Payload
+	Reason string `json:"reason,omitempty"`
+}
+
+// UptimeCheckRecoveredEvent is sent when a site is back up.
+type UptimeCheckRecoveredEvent struct {
+	Payload
+	DowntimePeriod *ohdear.DowntimePeriods `json:"downtimePeriod,omitempty"`
+}
+
+// Downtime returns the duration of the outage, it is zero
+// when the downtime period is incomplete.
+func (e *UptimeCheckRecoveredEvent) Downtime() time.Duration {
+	dp := e.DowntimePeriod
+	if dp == nil || dp.StartedAt == nil || dp.EndedAt == nil {
+		return 0
+	}
+
+	return dp.EndedAt.Sub(dp.StartedAt.Time)
+}
+
+// BrokenLinksEvent is sent when broken links are found or fixed.
+type BrokenLinksEvent struct {
+	Payload
+	BrokenLinks []*ohdear.BrokenLink `json:"brokenLinks,omitempty"`
+}
+
+// MixedContentEvent is sent when mixed content is found or fixed.
+type MixedContentEvent struct {
+	Payload
+	MixedContent []*ohdear.MixedContent `json:"mixedContent,omitempty"`
+}
+
+// CertificateEvent is sent when the certificate of a site
+// changes, is about to expire, is unhealthy or gets fixed.
+type CertificateEvent struct {
+	Payload
+	Certificate *ohdear.CertificateDetails `json:"certificate,omitempty"`
+	// FailedChecks lists the failed verifications of unhealthy certificates.
+	FailedChecks []*ohdear.CertificateCheck `json:"failedChecks,omitempty"`
+}
+
+// UnknownEvent holds events without a dedicated type.
+type UnknownEvent struct {
+	Payload
+}
+
+// ParseEvent decodes a webhook body into its typed event, unknown
+// event types are decoded as *UnknownEvent.
+func ParseEvent(body []byte) (Event, error) {
+	var p Payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	if p.Type == "" {
+		return nil, fmt.Errorf("%w: missing event type", ErrInvalidPayload)
+	}
+
+	var e Event
+	switch p.Type {
+	case UptimeCheckFailed:
+		e = new(UptimeCheckFailedEvent)
+	case UptimeCheckRecovered:
+		e = new(UptimeCheckRecoveredEvent)
+	case BrokenLinksFound, BrokenLinksFixed:
+		e = new(BrokenLinksEvent)
+	case MixedContentFound, MixedContentFixed:
+		e = new(MixedContentEvent)
+	case CertificateExpiresSoon, CertificateHasChanged, CertificateUnhealthy, CertificateFixed:
+		e = new(CertificateEvent)
+	default:
+		e = new(UnknownEvent)
+	}
+
+	if err := json.Unmarshal(body, e); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	e.Meta().Raw = append(json.RawMessage(nil), body...)
+
+	return e, nil
+}
@@ -0,0 +1,289 @@
+// Package webhook receives the notifications oh-dear posts to
+// webhook destinations.
+//
+// Every call is signed with the secret configured for the destination,
+// the hex encoded HMAC-SHA256 of the body is sent in the Signature
+// header. The Handler verifies it in constant time, rejects events
+// older than the tolerance as well as already delivered calls, and
+// dispatches the typed event to the function registered for its type.
+//
+//	h := webhook.NewHandler(os.Getenv("OHDEAR_WEBHOOK_SECRET"))
+//	h.HandleFunc(webhook.UptimeCheckFailed, func(ctx context.Context, e webhook.Event) error {
+//		failed := e.(*webhook.UptimeCheckFailedEvent)
+//		log.Printf("%s is down: %s", failed.Site.URL, failed.Reason)
+//		return nil
+//	})
+//	http.Handle("/ohdear", h)
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Webhook related constants.
+const (
+	// SignatureHeader contains the signature of the body.
+	SignatureHeader = "Signature"
+	// DefaultTolerance is the maximum age of the accepted events.
+	DefaultTolerance = 5 * time.Minute
+	// RetryWindow is how long the retries of a failed event are
+	// accepted regardless of the tolerance.
+	RetryWindow = 24 * time.Hour
+	// MaxBodySize is the largest accepted body.
+	MaxBodySize = 1 << 20
+)
+
+// Package level errors
+var (
+	ErrInvalidSignature error = fmt.Errorf("invalid webhook signature")
+	ErrInvalidPayload   error = fmt.Errorf("invalid webhook payload")
+	ErrStaleEvent       error = fmt.Errorf("the event is outside of the tolerance window")
+)
+
+// EventHandlerFunc processes a verified event, returning an error
+// answers with a server error so oh-dear retries the call.
+type EventHandlerFunc func(ctx context.Context, e Event) error
+
+// Handler verifies and dispatches the webhook calls.
+type Handler struct {
+	// Tolerance is the maximum difference between the event time and
+	// the current time, negative values disable the check and zero
+	// uses DefaultTolerance.
+	//
+	// Oh Dear retries the calls answered with a server error using the
+	// original event time, so once an event failed its retries skip the
+	// check during RetryWindow. Failures are kept in memory, retries
+	// reaching another process or a restarted one are still checked.
+	Tolerance time.Duration
+	// ErrorLog receives the rejected calls and the handler failures,
+	// the standard logger is used when nil.
+	ErrorLog *log.Logger
+
+	secret   []byte
+	now      func() time.Time
+	mu       sync.Mutex
+	handlers map[EventType]EventHandlerFunc
+	fallback EventHandlerFunc
+	seen     map[string]time.Time
+	failed   map[string]time.Time
+}
+
+// NewHandler returns a handler verifying the calls with the given secret.
+func NewHandler(secret string) *Handler {
+	return &Handler{
+		secret:   []byte(secret),
+		now:      time.Now,
+		handlers: make(map[EventType]EventHandlerFunc),
+		seen:     make(map[string]time.Time),
+		failed:   make(map[string]time.Time),
+	}
+}
+
+// HandleFunc registers the function processing the events of a type.
+func (h *Handler) HandleFunc(t EventType, f EventHandlerFunc) {
+	h.mu.Lock()
+	h.handlers[t] = f
+	h.mu.Unlock()
+}
+
+// HandleDefault registers the function processing the events
+// without a dedicated function, they are ignored otherwise.
+func (h *Handler) HandleDefault(f EventHandlerFunc) {
+	h.mu.Lock()
+	h.fallback = f
+	h.mu.Unlock()
+}
+
+// ServeHTTP verifies the call and dispatches its event.
+//
+// Invalid signatures are answered with 401, invalid or stale payloads
+// with 400 and replayed calls are acknowledged without being dispatched.
+// Handler failures are answered with 500 so the call is retried.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if len(body) > MaxBodySize {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	signature := strings.ToLower(r.Header.Get(SignatureHeader))
+	if !VerifySignature(h.secret, body, signature) {
+		h.logf("rejected call from %s: %v", r.RemoteAddr, ErrInvalidSignature)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	e, err := ParseEvent(body)
+	if err == nil && !h.retrying(signature) {
+		err = h.checkTime(e)
+	}
+	if err != nil {
+		h.logf("rejected call from %s: %v", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.remember(signature) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := h.dispatch(r.Context(), e); err != nil {
+		h.forget(signature)
+		h.logf("%s event failed: %v", e.Meta().Type, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) dispatch(ctx context.Context, e Event) error {
+	h.mu.Lock()
+	f, ok := h.handlers[e.Meta().Type]
+	if !ok {
+		f = h.fallback
+	}
+	h.mu.Unlock()
+
+	if f == nil {
+		return nil
+	}
+
+	return f(ctx, e)
+}
+
+func (h *Handler) tolerance() time.Duration {
+	if h.Tolerance == 0 {
+		return DefaultTolerance
+	}
+
+	return h.Tolerance
+}
+
+func (h *Handler) checkTime(e Event) error {
+	tol := h.tolerance()
+	if tol < 0 {
+		return nil
+	}
+
+	t, err := e.Meta().Time()
+	if err != nil {
+		return fmt.Errorf("%w: invalid dateTime %q", ErrInvalidPayload, e.Meta().DateTime)
+	}
+
+	if d := h.now().Sub(t); d > tol || d < -tol {
+		return fmt.Errorf("%w: sent at %s", ErrStaleEvent, t.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// remember records a delivered signature and reports whether it was
+// unknown, signatures are kept while their events can be accepted.
+func (h *Handler) remember(signature string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	for s, exp := range h.seen {
+		if now.After(exp) {
+			delete(h.seen, s)
+		}
+	}
+
+	for s, exp := range h.failed {
+		if now.After(exp) {
+			delete(h.failed, s)
+		}
+	}
+
+	if _, ok := h.seen[signature]; ok {
+		return false
+	}
+
+	ttl := h.tolerance()
+	if ttl < 0 {
+		ttl = DefaultTolerance
+	}
+	h.seen[signature] = now.Add(2 * ttl)
+	delete(h.failed, signature)
+
+	return true
+}
+
+// forget drops a signature whose event failed, so its retries
+// are dispatched again during RetryWindow.
+func (h *Handler) forget(signature string) {
+	h.mu.Lock()
+	delete(h.seen, signature)
+	h.failed[signature] = h.now().Add(RetryWindow)
+	h.mu.Unlock()
+}
+
+// retrying reports whether the signature belongs to a failed event.
+func (h *Handler) retrying(signature string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	exp, ok := h.failed[signature]
+
+	return ok && !h.now().After(exp)
+}
+
+func (h *Handler) logf(format string, args ...interface{}) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf(format, args...)
+		return
+	}
+
+	log.Printf("webhook: "+format, args...)
+}
+
+// Sign returns the signature oh-dear sends for the body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether the signature matches the body,
+// the comparison is done in constant time.
+func VerifySignature(secret, body []byte, signature string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
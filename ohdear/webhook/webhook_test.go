@@ -0,0 +1,217 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VictorAvelar/goh-dear/ohdear"
+	"github.com/VictorAvelar/goh-dear/testdata"
+)
+
+const testSecret = "webhook_secret"
+
+func newTestHandler(now time.Time) *Handler {
+	h := NewHandler(testSecret)
+	h.ErrorLog = log.New(ioutil.Discard, "", 0)
+	h.now = func() time.Time { return now }
+
+	return h
+}
+
+func post(h http.Handler, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/ohdear", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, signature)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(testdata.UptimeCheckFailedWebhook)
+	sig := Sign([]byte(testSecret), body)
+
+	assert.True(t, VerifySignature([]byte(testSecret), body, sig))
+	assert.False(t, VerifySignature([]byte("other"), body, sig))
+	assert.False(t, VerifySignature([]byte(testSecret), append(body, ' '), sig))
+	assert.False(t, VerifySignature([]byte(testSecret), body, "not-hex"))
+	assert.False(t, VerifySignature(nil, body, Sign(nil, body)))
+}
+
+func TestParseEvent(t *testing.T) {
+	cases := []struct {
+		name  string
+		body  string
+		check func(t *testing.T, e Event)
+	}{
+		{
+			"uptime check failed",
+			testdata.UptimeCheckFailedWebhook,
+			func(t *testing.T, e Event) {
+				f, ok := e.(*UptimeCheckFailedEvent)
+				assert.True(t, ok)
+				assert.Equal(t, uint(1), f.Site.ID)
+				assert.Equal(t, ohdear.UptimeCheck, f.Check.Type)
+				assert.Equal(t, ohdear.CheckFailed, f.Run.Result)
+				assert.Equal(t, "Connection timed out after 5 seconds", f.Reason)
+			},
+		},
+		{
+			"uptime check recovered",
+			testdata.UptimeCheckRecoveredWebhook,
+			func(t *testing.T, e Event) {
+				r, ok := e.(*UptimeCheckRecoveredEvent)
+				assert.True(t, ok)
+				assert.Equal(t, 14*time.Minute+30*time.Second, r.Downtime())
+			},
+		},
+		{
+			"broken links found",
+			testdata.BrokenLinksFoundWebhook,
+			func(t *testing.T, e Event) {
+				b, ok := e.(*BrokenLinksEvent)
+				assert.True(t, ok)
+				assert.Equal(t, BrokenLinksFound, b.Type)
+				assert.Len(t, b.BrokenLinks, 1)
+				assert.Equal(t, http.StatusNotFound, b.BrokenLinks[0].StatusCode)
+			},
+		},
+		{
+			"certificate expires soon",
+			testdata.CertificateExpiresSoonWebhook,
+			func(t *testing.T, e Event) {
+				c, ok := e.(*CertificateEvent)
+				assert.True(t, ok)
+				assert.Equal(t, "yourothersite.tld", c.Certificate.Domain)
+			},
+		},
+		{
+			"unknown event",
+			testdata.UnknownWebhook,
+			func(t *testing.T, e Event) {
+				u, ok := e.(*UnknownEvent)
+				assert.True(t, ok)
+				assert.Equal(t, EventType("somethingNew"), u.Type)
+				assert.JSONEq(t, testdata.UnknownWebhook, string(u.Raw))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			e, err := ParseEvent([]byte(c.body))
+			assert.Nil(t, err)
+			c.check(t, e)
+
+			ts, err := e.Meta().Time()
+			assert.Nil(t, err)
+			assert.Equal(t, 2020, ts.Year())
+		})
+	}
+
+	for _, body := range []string{"{", `{"dateTime":"20200801120000"}`} {
+		_, err := ParseEvent([]byte(body))
+		assert.True(t, errors.Is(err, ErrInvalidPayload), body)
+	}
+}
+
+func TestHandler_Dispatch(t *testing.T) {
+	h := newTestHandler(time.Date(2020, 8, 1, 12, 1, 0, 0, time.UTC))
+
+	var failed, other []Event
+	h.HandleFunc(UptimeCheckFailed, func(ctx context.Context, e Event) error {
+		failed = append(failed, e)
+		return nil
+	})
+	h.HandleDefault(func(ctx context.Context, e Event) error {
+		other = append(other, e)
+		return nil
+	})
+
+	body := testdata.UptimeCheckFailedWebhook
+	rec := post(h, body, Sign([]byte(testSecret), []byte(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, failed, 1)
+
+	rec = post(h, body, strings.ToUpper(Sign([]byte(testSecret), []byte(body))))
+	assert.Equal(t, http.StatusOK, rec.Code, "replays are acknowledged")
+	assert.Len(t, failed, 1, "replays are not dispatched")
+
+	body = testdata.BrokenLinksFoundWebhook
+	rec = post(h, body, Sign([]byte(testSecret), []byte(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, other, 1)
+}
+
+func TestHandler_Rejections(t *testing.T) {
+	h := newTestHandler(time.Date(2020, 8, 1, 12, 1, 0, 0, time.UTC))
+
+	called := 0
+	h.HandleDefault(func(ctx context.Context, e Event) error {
+		called++
+		return nil
+	})
+
+	body := testdata.UptimeCheckFailedWebhook
+	assert.Equal(t, http.StatusUnauthorized, post(h, body, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, post(h, body, Sign([]byte("other"), []byte(body))).Code)
+
+	stale := testdata.UptimeCheckRecoveredWebhook
+	h.now = func() time.Time { return time.Date(2020, 8, 1, 13, 0, 0, 0, time.UTC) }
+	assert.Equal(t, http.StatusBadRequest, post(h, stale, Sign([]byte(testSecret), []byte(stale))).Code)
+
+	h.Tolerance = -1
+	assert.Equal(t, http.StatusOK, post(h, stale, Sign([]byte(testSecret), []byte(stale))).Code)
+
+	invalid := `{"type":"uptimeCheckFailed","dateTime":"yesterday"}`
+	h.Tolerance = 0
+	assert.Equal(t, http.StatusBadRequest, post(h, invalid, Sign([]byte(testSecret), []byte(invalid))).Code)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ohdear", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	assert.Equal(t, 1, called)
+}
+
+func TestHandler_FailureAllowsRetry(t *testing.T) {
+	h := newTestHandler(time.Date(2020, 8, 1, 12, 1, 0, 0, time.UTC))
+
+	calls := 0
+	h.HandleFunc(UptimeCheckFailed, func(ctx context.Context, e Event) error {
+		calls++
+		if calls == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+
+	body := testdata.UptimeCheckFailedWebhook
+	sig := Sign([]byte(testSecret), []byte(body))
+	assert.Equal(t, http.StatusInternalServerError, post(h, body, sig).Code)
+
+	h.now = func() time.Time { return time.Date(2020, 8, 1, 13, 0, 0, 0, time.UTC) }
+	assert.Equal(t, http.StatusOK, post(h, body, sig).Code, "late retries of failed events are accepted")
+	assert.Equal(t, 2, calls)
+
+	h = newTestHandler(time.Date(2020, 8, 1, 12, 1, 0, 0, time.UTC))
+	h.HandleFunc(UptimeCheckFailed, func(ctx context.Context, e Event) error {
+		calls++
+		return errors.New("permanent failure")
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, post(h, body, sig).Code)
+	h.now = func() time.Time { return time.Date(2020, 8, 2, 14, 0, 0, 0, time.UTC) }
+	assert.Equal(t, http.StatusBadRequest, post(h, body, sig).Code, "retries after the retry window are stale")
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,100 @@
+package testdata
+
+const ApplicationHealthChecksResponse = `{
+  "data": [
+    {
+      "id": 1,
+      "name": "UsedDiskSpace",
+      "label": "Used disk space",
+      "status": "warning",
+      "short_summary": "76%",
+      "message": "The disk is almost full (76% used)",
+      "meta": {
+        "disk_space_used_percentage": 76
+      },
+      "detected_at": "2021-04-08 14:15:20",
+      "updated_at": "2021-04-08 14:15:20"
+    },
+    {
+      "id": 2,
+      "name": "Database",
+      "label": "Database",
+      "status": "ok",
+      "short_summary": "Ok",
+      "message": "",
+      "meta": {},
+      "detected_at": "2021-04-08 14:15:20",
+      "updated_at": "2021-04-08 14:15:20"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sites/1/application-health-checks?page=1",
+    "last": "https://ohdear.app/api/sites/1/application-health-checks?page=1",
+    "prev": null,
+    "next": null
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 1,
+    "path": "https://ohdear.app/api/sites/1/application-health-checks",
+    "per_page": 15,
+    "to": 2,
+    "total": 2
+  }
+}`
+
+const ApplicationHealthCheckResultsResponse = `{
+  "data": [
+    {
+      "id": 10,
+      "status": "warning",
+      "short_summary": "76%",
+      "message": "The disk is almost full (76% used)",
+      "meta": {
+        "disk_space_used_percentage": 76
+      },
+      "detected_at": "2021-04-08 14:15:20"
+    },
+    {
+      "id": 9,
+      "status": "ok",
+      "short_summary": "61%",
+      "message": "",
+      "meta": {
+        "disk_space_used_percentage": 61
+      },
+      "detected_at": "2021-04-07 14:15:20"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sites/1/application-health-checks/1?page=1",
+    "last": "https://ohdear.app/api/sites/1/application-health-checks/1?page=2",
+    "prev": null,
+    "next": "https://ohdear.app/api/sites/1/application-health-checks/1?page=2"
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 2,
+    "path": "https://ohdear.app/api/sites/1/application-health-checks/1",
+    "per_page": 2,
+    "to": 2,
+    "total": 3
+  }
+}`
+
+const SnoozedApplicationHealthCheckResponse = `{
+  "id": 1,
+  "name": "UsedDiskSpace",
+  "label": "Used disk space",
+  "status": "warning",
+  "short_summary": "76%",
+  "message": "The disk is almost full (76% used)",
+  "meta": {
+    "disk_space_used_percentage": 76
+  },
+  "detected_at": "2021-04-08 14:15:20",
+  "updated_at": "2021-04-08 14:15:20",
+  "snoozed_until": "2021-04-08 15:15:20"
+}`
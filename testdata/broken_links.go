@@ -0,0 +1,39 @@
+package testdata
+
+const BrokenLinksResponse = `{
+  "data": [
+    {
+      "crawled_url": "https://yoursite.tld/missing",
+      "status_code": 404,
+      "found_on_url": "https://yoursite.tld/blog",
+      "link_text": "Read more"
+    },
+    {
+      "crawled_url": "https://external.tld/gone",
+      "status_code": 410,
+      "found_on_url": "https://yoursite.tld/about",
+      "link_text": "Partner"
+    },
+    {
+      "crawled_url": "https://yoursite.tld/old-post",
+      "status_code": 404,
+      "found_on_url": "https://yoursite.tld/blog",
+      "link_text": "Old post"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/broken-links/1?page=1",
+    "last": "https://ohdear.app/api/broken-links/1?page=1",
+    "prev": null,
+    "next": null
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 1,
+    "path": "https://ohdear.app/api/broken-links/1",
+    "per_page": 15,
+    "to": 3,
+    "total": 3
+  }
+}`
@@ -0,0 +1,31 @@
+package testdata
+
+const CertificateHealthResponse = `{
+  "certificate_details": {
+    "issuer": "Let's Encrypt Authority X3",
+    "domain": "yourothersite.tld",
+    "additional_domains": [
+      "www.yourothersite.tld",
+      "yourothersite.tld"
+    ],
+    "fingerprint": "9B:5C:2E:8F:51:CF:4A:27:6A:D9:7B:3C:1A:0F:21:C4:A1:E2:7D:36",
+    "valid_from": "2019-08-28 14:48:11",
+    "valid_until": "2019-11-26 14:48:11"
+  },
+  "certificate_checks": [
+    {
+      "type": "notExpired",
+      "label": "Not expired",
+      "passed": true
+    },
+    {
+      "type": "coversURL",
+      "label": "Covers the domain of your site",
+      "passed": false
+    }
+  ],
+  "certificate_chain_issuers": [
+    "Let's Encrypt Authority X3",
+    "DST Root CA X3"
+  ]
+}`
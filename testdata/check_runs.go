@@ -0,0 +1,91 @@
+package testdata
+
+const CheckRunsPage1Response = `{
+  "data": [
+    {
+      "id": 1001,
+      "check_id": 100,
+      "result": "succeeded",
+      "summary": "Up",
+      "started_at": "2020-08-01 10:00:00",
+      "ended_at": "2020-08-01 10:00:02"
+    },
+    {
+      "id": 1002,
+      "check_id": 100,
+      "result": "failed",
+      "summary": "Connection timed out",
+      "notification_message": "yoursite.tld is down: connection timed out",
+      "started_at": "2020-08-01 10:01:00",
+      "ended_at": "2020-08-01 10:01:30"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/checks/100/runs?page%5Bnumber%5D=1",
+    "last": "https://ohdear.app/api/checks/100/runs?page%5Bnumber%5D=2",
+    "prev": null,
+    "next": "https://ohdear.app/api/checks/100/runs?page%5Bnumber%5D=2"
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 2,
+    "path": "https://ohdear.app/api/checks/100/runs",
+    "per_page": 2,
+    "to": 2,
+    "total": 6
+  }
+}`
+
+const CheckRunsPage2Response = `{
+  "data": [
+    {
+      "id": 1003,
+      "check_id": 100,
+      "result": "failed",
+      "summary": "Connection timed out",
+      "started_at": "2020-08-01 10:02:00",
+      "ended_at": "2020-08-01 10:02:30"
+    },
+    {
+      "id": 1004,
+      "check_id": 100,
+      "result": "succeeded",
+      "summary": "Up",
+      "notification_message": "yoursite.tld is back up",
+      "started_at": "2020-08-01 10:03:00",
+      "ended_at": "2020-08-01 10:03:01"
+    },
+    {
+      "id": 1005,
+      "check_id": 100,
+      "result": "succeeded",
+      "summary": "Up",
+      "started_at": "2020-08-01 23:59:50",
+      "ended_at": "2020-08-02 00:00:10"
+    },
+    {
+      "id": 1006,
+      "check_id": 100,
+      "result": "succeeded",
+      "summary": "Up",
+      "started_at": "2020-08-02 00:00:50",
+      "ended_at": null
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/checks/100/runs?page%5Bnumber%5D=1",
+    "last": "https://ohdear.app/api/checks/100/runs?page%5Bnumber%5D=2",
+    "prev": "https://ohdear.app/api/checks/100/runs?page%5Bnumber%5D=1",
+    "next": null
+  },
+  "meta": {
+    "current_page": 2,
+    "from": 3,
+    "last_page": 2,
+    "path": "https://ohdear.app/api/checks/100/runs",
+    "per_page": 2,
+    "to": 6,
+    "total": 6
+  }
+}`
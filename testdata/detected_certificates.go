@@ -0,0 +1,45 @@
+package testdata
+
+const DetectedCertificatesResponse = `{
+  "data": [
+    {
+      "id": 1,
+      "fingerprint": "9B:5C:2E:8F:51:CF:4A:27:6A:D9:7B:3C:1A:0F:21:C4:A1:E2:7D:36",
+      "certificate_details": {
+        "issuer": "Let's Encrypt Authority X3",
+        "domain": "yourothersite.tld",
+        "additional_domains": ["www.yourothersite.tld"],
+        "valid_from": "2019-08-28 14:48:11",
+        "valid_until": "2019-11-26 14:48:11"
+      },
+      "created_at": "2019-08-28 15:01:43"
+    },
+    {
+      "id": 2,
+      "fingerprint": "1E:01:BA:43:22:6C:0F:71:D1:37:8A:50:4C:9A:EF:10:5B:C7:11:42",
+      "certificate_details": {
+        "issuer": "Unknown Issuing CA",
+        "domain": "yourothersite.tld",
+        "additional_domains": [],
+        "valid_from": "2019-09-02 00:00:00",
+        "valid_until": "2020-09-02 00:00:00"
+      },
+      "created_at": "2019-09-02 08:12:00"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sites/2/detected-certificates?page=1",
+    "last": "https://ohdear.app/api/sites/2/detected-certificates?page=1",
+    "prev": null,
+    "next": null
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 1,
+    "path": "https://ohdear.app/api/sites/2/detected-certificates",
+    "per_page": 15,
+    "to": 2,
+    "total": 2
+  }
+}`
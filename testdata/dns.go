@@ -0,0 +1,61 @@
+package testdata
+
+const DNSHistoryResponse = `{
+  "data": [
+    {
+      "id": 8,
+      "authoritative_nameservers": ["ns1.example.net", "ns2.example.net"],
+      "dns_records": [
+        {"host": "yoursite.tld", "class": "IN", "ttl": 300, "type": "A", "ip": "203.0.113.20"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 300, "type": "AAAA", "ipv6": "2001:db8::20"},
+        {"host": "www.yoursite.tld", "class": "IN", "ttl": 3600, "type": "CNAME", "target": "yoursite.tld"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 3600, "type": "MX", "pri": 10, "target": "mx1.example.net"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 86400, "type": "NS", "target": "ns1.example.net"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 300, "type": "TXT", "txt": "v=spf1 include:_spf.example.net ~all"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 3600, "type": "SOA", "mname": "ns1.example.net", "rname": "hostmaster.yoursite.tld", "serial": 2020080201, "refresh": 7200, "retry": 3600, "expire": 1209600, "minimum_ttl": 300},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 3600, "type": "CAA", "flags": 0, "tag": "issue", "value": "letsencrypt.org"}
+      ],
+      "created_at": "2020-08-02 06:00:00"
+    },
+    {
+      "id": 7,
+      "authoritative_nameservers": ["ns1.example.net", "ns2.example.net"],
+      "dns_records": [
+        {"host": "yoursite.tld", "class": "IN", "ttl": 300, "type": "A", "ip": "203.0.113.10"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 300, "type": "AAAA", "ipv6": "2001:db8::20"},
+        {"host": "www.yoursite.tld", "class": "IN", "ttl": 3600, "type": "CNAME", "target": "yoursite.tld"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 3600, "type": "MX", "pri": 10, "target": "mx1.example.net"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 3600, "type": "MX", "pri": 20, "target": "mx2.example.net"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 3600, "type": "NS", "target": "ns1.example.net"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 300, "type": "TXT", "txt": "v=spf1 include:_spf.example.net ~all"},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 3600, "type": "SOA", "mname": "ns1.example.net", "rname": "hostmaster.yoursite.tld", "serial": 2020080101, "refresh": 7200, "retry": 3600, "expire": 1209600, "minimum_ttl": 300},
+        {"host": "yoursite.tld", "class": "IN", "ttl": 3600, "type": "CAA", "flags": 0, "tag": "issue", "value": "letsencrypt.org"}
+      ],
+      "created_at": "2020-08-01 06:00:00"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sites/1/dns-history-items?page%5Bnumber%5D=1",
+    "last": "https://ohdear.app/api/sites/1/dns-history-items?page%5Bnumber%5D=1",
+    "prev": null,
+    "next": null
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 1,
+    "path": "https://ohdear.app/api/sites/1/dns-history-items",
+    "per_page": 15,
+    "to": 2,
+    "total": 2
+  }
+}`
+
+const DNSSnapshotResponse = `{
+  "id": 7,
+  "authoritative_nameservers": ["ns1.example.net", "ns2.example.net"],
+  "dns_records": [
+    {"host": "yoursite.tld", "class": "IN", "ttl": 300, "type": "A", "ip": "203.0.113.10"}
+  ],
+  "created_at": "2020-08-01 06:00:00"
+}`
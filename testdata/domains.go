@@ -0,0 +1,12 @@
+package testdata
+
+const DomainResponse = `{
+  "domain": "yoursite.tld",
+  "registrar": "Example Registrar, Inc.",
+  "registered_at": "2010-03-14 09:21:00",
+  "expires_at": "2021-03-14 09:21:00",
+  "nameservers": ["ns1.example.net", "ns2.example.net"],
+  "status_codes": ["client transfer prohibited", "client delete prohibited"],
+  "source": "rdap",
+  "checked_at": "2020-08-01 04:00:00"
+}`
@@ -0,0 +1,70 @@
+package testdata
+
+const LighthouseReportsResponse = `{
+  "data": [
+    {
+      "id": 12,
+      "performance_score": 91,
+      "accessibility_score": 98,
+      "best_practices_score": 100,
+      "seo_score": 92,
+      "progressive_web_app_score": 30,
+      "first_contentful_paint_in_ms": 812.4,
+      "largest_contentful_paint_in_ms": 1530.2,
+      "speed_index_in_ms": 1204,
+      "time_to_interactive_in_ms": 2100.5,
+      "total_blocking_time_in_ms": 60,
+      "cumulative_layout_shift": 0.02,
+      "performed_on_checker_server": "Frankfurt",
+      "created_at": "2020-08-02 04:00:00"
+    },
+    {
+      "id": 11,
+      "performance_score": 95,
+      "accessibility_score": 98,
+      "best_practices_score": 100,
+      "seo_score": 92,
+      "progressive_web_app_score": 30,
+      "first_contentful_paint_in_ms": 790,
+      "largest_contentful_paint_in_ms": 1210,
+      "speed_index_in_ms": 1180,
+      "time_to_interactive_in_ms": 2050,
+      "total_blocking_time_in_ms": 40,
+      "cumulative_layout_shift": 0.02,
+      "performed_on_checker_server": "Frankfurt",
+      "created_at": "2020-08-01 04:00:00"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sites/1/lighthouse-reports?page%5Bnumber%5D=1",
+    "last": "https://ohdear.app/api/sites/1/lighthouse-reports?page%5Bnumber%5D=1",
+    "prev": null,
+    "next": null
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 1,
+    "path": "https://ohdear.app/api/sites/1/lighthouse-reports",
+    "per_page": 15,
+    "to": 2,
+    "total": 2
+  }
+}`
+
+const LighthouseReportResponse = `{
+  "id": 12,
+  "performance_score": 91,
+  "accessibility_score": 98,
+  "best_practices_score": 100,
+  "seo_score": 92,
+  "progressive_web_app_score": 30,
+  "first_contentful_paint_in_ms": 812.4,
+  "largest_contentful_paint_in_ms": 1530.2,
+  "speed_index_in_ms": 1204,
+  "time_to_interactive_in_ms": 2100.5,
+  "total_blocking_time_in_ms": 60,
+  "cumulative_layout_shift": 0.02,
+  "performed_on_checker_server": "Frankfurt",
+  "created_at": "2020-08-02 04:00:00"
+}`
@@ -0,0 +1,36 @@
+package testdata
+
+const MixedContentResponse = `{
+  "data": [
+    {
+      "element_name": "img",
+      "mixed_content_url": "http://cdn.tld/logo.png",
+      "found_on_url": "https://yourothersite.tld/"
+    },
+    {
+      "element_name": "script",
+      "mixed_content_url": "http://tracker.tld/t.js",
+      "found_on_url": "https://yourothersite.tld/"
+    },
+    {
+      "element_name": "img",
+      "mixed_content_url": "http://cdn.tld/banner.png",
+      "found_on_url": "https://yourothersite.tld/about"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/mixed-content/2?page=1",
+    "last": "https://ohdear.app/api/mixed-content/2?page=1",
+    "prev": null,
+    "next": null
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 1,
+    "path": "https://ohdear.app/api/mixed-content/2",
+    "per_page": 15,
+    "to": 3,
+    "total": 3
+  }
+}`
@@ -0,0 +1,46 @@
+package testdata
+
+const NotificationDestinationsResponse = `{
+  "data": [
+    {
+      "id": 1,
+      "channel": "mail",
+      "destination": {
+        "mail": "ops@example.com"
+      },
+      "notification_types": [
+        "UptimeCheckFailedNotification",
+        "UptimeCheckRecoveredNotification"
+      ],
+      "created_at": "2019-09-16 11:43:34",
+      "updated_at": "2019-09-16 11:43:34"
+    },
+    {
+      "id": 2,
+      "channel": "pushover",
+      "destination": {
+        "user_key": "uQiRzpo4DXghDmr9QzzfQu27cmVRsG",
+        "priority": 1
+      },
+      "notification_types": [
+        "CertificateExpiresSoonNotification"
+      ],
+      "created_at": "2019-09-17 08:12:00",
+      "updated_at": "2019-09-18 09:30:00"
+    }
+  ]
+}`
+
+const NotificationDestinationResponse = `{
+  "id": 3,
+  "channel": "slack",
+  "destination": {
+    "url": "https://hooks.slack.com/services/T000/B000/XXXX",
+    "channel": "#alerts"
+  },
+  "notification_types": [
+    "UptimeCheckFailedNotification"
+  ],
+  "created_at": "2019-09-20 10:00:00",
+  "updated_at": "2019-09-20 10:00:00"
+}`
@@ -0,0 +1,77 @@
+package testdata
+
+const PerformanceRecordsPage1Response = `{
+  "data": [
+    {
+      "id": 1,
+      "site_id": 1,
+      "dns_time_in_seconds": 0.002,
+      "tcp_time_in_seconds": 0.011,
+      "ssl_handshake_time_in_seconds": 0.034,
+      "remote_server_processing_time_in_seconds": 0.120,
+      "download_time_in_seconds": 0.003,
+      "total_time_in_seconds": 0.170,
+      "measured_from": "Frankfurt",
+      "created_at": "2020-08-01 10:05:00"
+    },
+    {
+      "id": 2,
+      "site_id": 1,
+      "dns_time_in_seconds": 0.003,
+      "tcp_time_in_seconds": 0.012,
+      "ssl_handshake_time_in_seconds": 0.031,
+      "remote_server_processing_time_in_seconds": 0.410,
+      "download_time_in_seconds": 0.004,
+      "total_time_in_seconds": 0.460,
+      "measured_from": "Frankfurt",
+      "created_at": "2020-08-01 10:35:00"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sites/1/performance-records?page%5Bnumber%5D=1",
+    "last": "https://ohdear.app/api/sites/1/performance-records?page%5Bnumber%5D=2",
+    "prev": null,
+    "next": "https://ohdear.app/api/sites/1/performance-records?page%5Bnumber%5D=2"
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 2,
+    "path": "https://ohdear.app/api/sites/1/performance-records",
+    "per_page": 2,
+    "to": 2,
+    "total": 3
+  }
+}`
+
+const PerformanceRecordsPage2Response = `{
+  "data": [
+    {
+      "id": 3,
+      "site_id": 1,
+      "dns_time_in_seconds": 0.002,
+      "tcp_time_in_seconds": 0.010,
+      "ssl_handshake_time_in_seconds": 0.030,
+      "remote_server_processing_time_in_seconds": 0.100,
+      "download_time_in_seconds": 0.002,
+      "total_time_in_seconds": 0.144,
+      "measured_from": "New York",
+      "created_at": "2020-08-01 11:05:00"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sites/1/performance-records?page%5Bnumber%5D=1",
+    "last": "https://ohdear.app/api/sites/1/performance-records?page%5Bnumber%5D=2",
+    "prev": "https://ohdear.app/api/sites/1/performance-records?page%5Bnumber%5D=1",
+    "next": null
+  },
+  "meta": {
+    "current_page": 2,
+    "from": 3,
+    "last_page": 2,
+    "path": "https://ohdear.app/api/sites/1/performance-records",
+    "per_page": 2,
+    "to": 3,
+    "total": 3
+  }
+}`
@@ -0,0 +1,94 @@
+package testdata
+
+const SitemapResponse = `{
+  "check_url": "https://yoursite.tld/sitemap.xml",
+  "total_url_count": 124,
+  "total_issues_count": 2,
+  "sitemap_indexes": [
+    {
+      "url": "https://yoursite.tld/sitemap.xml",
+      "url_count": 0,
+      "issues": []
+    }
+  ],
+  "sitemaps": [
+    {
+      "url": "https://yoursite.tld/sitemap-posts.xml",
+      "url_count": 120,
+      "issues": [
+        {
+          "type": "url_error",
+          "name": "The url responded with a 404 status code",
+          "sitemap_url": "https://yoursite.tld/sitemap-posts.xml",
+          "url": "https://yoursite.tld/posts/removed",
+          "status_code": 404
+        }
+      ]
+    },
+    {
+      "url": "https://yoursite.tld/sitemap-pages.xml",
+      "url_count": 4,
+      "issues": [
+        {
+          "type": "invalid_xml",
+          "name": "The sitemap is not valid XML",
+          "sitemap_url": "https://yoursite.tld/sitemap-pages.xml"
+        }
+      ]
+    }
+  ],
+  "issues": [],
+  "checked_at": "2020-08-01 04:00:00"
+}`
+
+const SitemapIssuesPage1Response = `{
+  "data": [
+    {
+      "type": "url_error",
+      "name": "The url responded with a 404 status code",
+      "sitemap_url": "https://yoursite.tld/sitemap-posts.xml",
+      "url": "https://yoursite.tld/posts/removed",
+      "status_code": 404
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sitemap/1/issues?page%5Bnumber%5D=1",
+    "last": "https://ohdear.app/api/sitemap/1/issues?page%5Bnumber%5D=2",
+    "prev": null,
+    "next": "https://ohdear.app/api/sitemap/1/issues?page%5Bnumber%5D=2"
+  },
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 2,
+    "path": "https://ohdear.app/api/sitemap/1/issues",
+    "per_page": 1,
+    "to": 1,
+    "total": 2
+  }
+}`
+
+const SitemapIssuesPage2Response = `{
+  "data": [
+    {
+      "type": "invalid_xml",
+      "name": "The sitemap is not valid XML",
+      "sitemap_url": "https://yoursite.tld/sitemap-pages.xml"
+    }
+  ],
+  "links": {
+    "first": "https://ohdear.app/api/sitemap/1/issues?page%5Bnumber%5D=1",
+    "last": "https://ohdear.app/api/sitemap/1/issues?page%5Bnumber%5D=2",
+    "prev": "https://ohdear.app/api/sitemap/1/issues?page%5Bnumber%5D=1",
+    "next": null
+  },
+  "meta": {
+    "current_page": 2,
+    "from": 2,
+    "last_page": 2,
+    "path": "https://ohdear.app/api/sitemap/1/issues",
+    "per_page": 1,
+    "to": 2,
+    "total": 2
+  }
+}`
@@ -10,8 +10,8 @@ const MultipleSitesResponse = `{
       "team_id": 1,
       "latest_run_date": "2019-09-16 07:29:02",
       "summarized_check_result": "succeeded",
-      "created_at": "20171106 07:40:49",
-      "updated_at": "20171106 07:40:49",
+      "created_at": "2017-11-06 07:40:49",
+      "updated_at": "2017-11-06 07:40:49",
       "checks": [
         {
           "id": 100,
@@ -28,19 +28,20 @@ const MultipleSitesResponse = `{
           "enabled": true,
           "latest_run_ended_at": "2019-09-16 07:29:05",
           "latest_run_result": "succeeded"
-        },
+        }
       ]
     },
     {
       "id": 2,
       "url": "https://yourothersite.tld",
       "sort_url": "yourothersite.tld",
+      "uses_https": true,
       "label": "my-site",
       "team_id": 1,
       "latest_run_date": "2019-09-16 07:29:02",
       "summarized_check_result": "failed",
-      "created_at": "20171108 07:40:16",
-      "updated_at": "20171108 07:40:16",
+      "created_at": "2017-11-08 07:40:16",
+      "updated_at": "2017-11-08 07:40:16",
       "checks": [
         {
           "id": 1,
@@ -87,6 +88,61 @@ const MultipleSitesResponse = `{
   ]
 }`
 
+const SitesFirstPageResponse = `{
+  "data": [
+    {
+      "id": 1,
+      "url": "http://yoursite.tld",
+      "sort_url": "yoursite.tld",
+      "label": "your-site",
+      "team_id": 1,
+      "summarized_check_result": "succeeded"
+    },
+    {
+      "id": 2,
+      "url": "https://yourothersite.tld",
+      "sort_url": "yourothersite.tld",
+      "uses_https": true,
+      "label": "my-site",
+      "team_id": 1,
+      "summarized_check_result": "failed"
+    }
+  ],
+  "meta": {
+    "current_page": 1,
+    "from": 1,
+    "last_page": 2,
+    "per_page": 2,
+    "to": 2,
+    "total": 3
+  }
+}`
+
+const SitesLastPageResponse = `{
+  "data": [
+    {
+      "id": 3,
+      "url": "https://yourlastsite.tld",
+      "sort_url": "yourlastsite.tld",
+      "uses_https": true,
+      "label": "last-site",
+      "team_id": 1,
+      "latest_run_date": "2019-09-16 07:29:02",
+      "summarized_check_result": "succeeded",
+      "created_at": "2017-11-09 07:40:16",
+      "updated_at": "2017-11-09 07:40:16"
+    }
+  ],
+  "meta": {
+    "current_page": 2,
+    "from": 3,
+    "last_page": 2,
+    "per_page": 2,
+    "to": 3,
+    "total": 3
+  }
+}`
+
 const SingleSiteResponse = `{
   "id": 1,
   "url": "http://yoursite.tld",
@@ -97,6 +153,11 @@ const SingleSiteResponse = `{
   "summarized_check_result": "succeeded",
   "created_at": "2017-11-06 07:40:49",
   "updated_at": "2017-11-06 07:40:49",
+  "broken_links_check_include_external_links": true,
+  "broken_links_whitelisted_urls": [
+	"https://yoursite.tld/ignored",
+	"https://external.tld/flaky"
+  ],
   "checks": [
 	{
 	  "id": 100,
@@ -118,14 +179,27 @@ const SingleSiteResponse = `{
 }`
 
 const UptimeResponse = `{
-data	[
-	{
-		datetime: 2018-09-22 12:00:00,
-		uptime_percentage:	99.98
-	},
-	{
-		datetime: 2018-09-23 12:00:00,
-		uptime_percentage:	98.00
-	}
-]
+  "data": [
+    {
+      "datetime": "2018-09-22 12:00:00",
+      "uptime_percentage": 99.98
+    },
+    {
+      "datetime": "2018-09-23 12:00:00",
+      "uptime_percentage": 98.00
+    }
+  ]
+}`
+
+const DowntimeResponse = `{
+  "data": [
+    {
+      "started_at": "2018-09-22 12:00:00",
+      "ended_at": "2018-09-22 12:05:00"
+    },
+    {
+      "started_at": "2018-09-23 08:00:00",
+      "ended_at": null
+    }
+  ]
 }`
@@ -0,0 +1,20 @@
+package testdata
+
+const MeResponse = `{
+  "id": 1,
+  "name": "Jane Doe",
+  "email": "jane@yoursite.tld",
+  "photo_url": "https://gravatar.com/avatar/1",
+  "teams": [
+    {
+      "id": 1,
+      "name": "Acme",
+      "role": "owner"
+    },
+    {
+      "id": 9,
+      "name": "Globex Ops",
+      "role": "member"
+    }
+  ]
+}`
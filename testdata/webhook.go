@@ -0,0 +1,86 @@
+package testdata
+
+const UptimeCheckFailedWebhook = `{
+  "type": "uptimeCheckFailed",
+  "dateTime": "20200801120000",
+  "site": {
+    "id": 1,
+    "url": "http://yoursite.tld",
+    "label": "your-site",
+    "team_id": 1
+  },
+  "check": {
+    "id": 100,
+    "type": "uptime",
+    "label": "Uptime",
+    "enabled": true
+  },
+  "run": {
+    "id": 5000,
+    "result": "failed",
+    "summary": "The site is down",
+    "started_at": "2020-08-01 11:59:58",
+    "ended_at": "2020-08-01 12:00:00"
+  },
+  "reason": "Connection timed out after 5 seconds"
+}`
+
+const UptimeCheckRecoveredWebhook = `{
+  "type": "uptimeCheckRecovered",
+  "dateTime": "20200801121500",
+  "site": {
+    "id": 1,
+    "url": "http://yoursite.tld",
+    "label": "your-site",
+    "team_id": 1
+  },
+  "downtimePeriod": {
+    "started_at": "2020-08-01 12:00:00",
+    "ended_at": "2020-08-01 12:14:30"
+  }
+}`
+
+const BrokenLinksFoundWebhook = `{
+  "type": "brokenLinksFound",
+  "dateTime": "20200801120000",
+  "site": {
+    "id": 2,
+    "url": "https://yourothersite.tld",
+    "label": "my-site",
+    "team_id": 1
+  },
+  "brokenLinks": [
+    {
+      "crawled_url": "https://yourothersite.tld/missing",
+      "status_code": 404,
+      "found_on_url": "https://yourothersite.tld/",
+      "link_text": "Missing page"
+    }
+  ]
+}`
+
+const CertificateExpiresSoonWebhook = `{
+  "type": "certificateExpiresSoon",
+  "dateTime": "20200801120000",
+  "site": {
+    "id": 2,
+    "url": "https://yourothersite.tld",
+    "label": "my-site",
+    "team_id": 1
+  },
+  "certificate": {
+    "issuer": "Let's Encrypt Authority X3",
+    "domain": "yourothersite.tld",
+    "valid_from": "2020-05-10 14:48:11",
+    "valid_until": "2020-08-08 14:48:11"
+  }
+}`
+
+const UnknownWebhook = `{
+  "type": "somethingNew",
+  "dateTime": "20200801120000",
+  "site": {
+    "id": 1,
+    "url": "http://yoursite.tld"
+  }
+}`